@@ -14,17 +14,35 @@ var buildQuiet bool
 
 var buildCmd = &cobra.Command{
 	Use:   "build",
-	Short: "Builds the C++ source file (default: main.cpp)",
+	Short: "Builds the source file (default: main.cpp)",
 	Long: `Builds the source file (default: main.cpp) using the compiler command specified in the configuration.
 By default, it uses 'g++' with standard compilation flags.
 The resulting executable will be named 'main'.
 
+Build profiles select other flags and executable names, e.g. 'fo build --profile debug'.
+The built-in profiles are release (the configured flags), debug and asan (address and
+undefined behavior sanitizers); more can be added under 'profiles' in the config.
+
+The build command depends on the language of the source file: when main.cpp does not
+exist, fo looks for main.c, main.py, main.java and so on, or '--lang python' picks one.
+Languages and their compile and run commands are defined under 'languages' in the config.
+
 You can customize the compiler command and flags in your configuration file.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		// Load config
 		if err := utils.LoadConfigOnce(false); err != nil {
 			return err
 		}
+		if err := applyProfile(cmd); err != nil {
+			return err
+		}
+		if err := applyLanguage(cmd); err != nil {
+			return err
+		}
+		if !utils.IsCompiled() {
+			fmt.Printf("%s%s needs no build, it is run by an interpreter.%s\n", colors.CYAN, utils.CmdConfig.SourceName, colors.RESET)
+			return nil
+		}
 
 		err := utils.BuildExecutable(buildQuiet)
 		if err != nil {
@@ -36,7 +54,37 @@ You can customize the compiler command and flags in your configuration file.`,
 	},
 }
 
+// addProfileFlag adds the --profile flag selecting a build profile.
+func addProfileFlag(cmd *cobra.Command) {
+	cmd.Flags().StringP("profile", "p", "", "Build profile from the config, e.g. release, debug or asan")
+}
+
+// applyProfile switches the config to the build profile selected by --profile, if any.
+func applyProfile(cmd *cobra.Command) error {
+	name, _ := cmd.Flags().GetString("profile")
+	if name == "" {
+		return nil
+	}
+	return utils.UseProfile(name)
+}
+
+// addLangFlag adds the --lang flag selecting the language of the solution.
+func addLangFlag(cmd *cobra.Command) {
+	cmd.Flags().StringP("lang", "l", "", "Language of the solution from the config, e.g. cpp, python or java")
+}
+
+// applyLanguage switches the source file to the language selected by --lang, if any.
+func applyLanguage(cmd *cobra.Command) error {
+	name, _ := cmd.Flags().GetString("lang")
+	if name == "" {
+		return nil
+	}
+	return utils.UseLanguage(name)
+}
+
 func init() {
 	buildCmd.Flags().BoolVarP(&buildQuiet, "quiet", "q", false, "Suppress build output")
+	addProfileFlag(buildCmd)
+	addLangFlag(buildCmd)
 	rootCmd.AddCommand(buildCmd)
 }
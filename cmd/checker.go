@@ -0,0 +1,226 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ahmedYasserM/fo/internal/utils"
+)
+
+// Checker decides whether a program's output is an acceptable answer.
+type Checker interface {
+	Check(input, output, expected string) CheckResult
+}
+
+// CheckResult is the verdict of a checker together with a human-readable explanation.
+type CheckResult struct {
+	Verdict Verdict
+	Message string
+}
+
+// defaultEpsilon is the float tolerance used when a float checker spec does not give one.
+const defaultEpsilon = 1e-6
+
+// helperTimeLimit bounds how long helper programs such as checkers, generators
+// and brute force solutions may run on one test.
+const helperTimeLimit = 10 * time.Second
+
+// parseChecker builds a checker from a spec such as "tokens", "nocase" or "float:1e-9".
+// A spec naming an existing file is used as a testlib-compatible checker program;
+// C++ sources are compiled first.
+func parseChecker(spec string, quiet bool) (Checker, error) {
+	spec = strings.TrimSpace(spec)
+	if spec != "" && utils.PathExists(spec) {
+		return newProgramChecker(spec, quiet)
+	}
+
+	name, arg, hasArg := strings.Cut(spec, ":")
+
+	switch name {
+	case "", "tokens":
+		return tokenChecker{}, nil
+	case "lines":
+		return lineChecker{}, nil
+	case "nocase":
+		return tokenChecker{ignoreCase: true}, nil
+	case "float":
+		epsilon := defaultEpsilon
+		if hasArg {
+			var err error
+			epsilon, err = strconv.ParseFloat(arg, 64)
+			if err != nil || epsilon < 0 {
+				return nil, fmt.Errorf("invalid float checker epsilon %q", arg)
+			}
+		}
+		return floatChecker{epsilon: epsilon}, nil
+	}
+	return nil, fmt.Errorf("unknown checker %q (expected lines, tokens, nocase, float[:epsilon] or a checker program)", spec)
+}
+
+// lineChecker compares the output line by line, ignoring trailing whitespace
+// on each line and trailing blank lines.
+type lineChecker struct{}
+
+func (lineChecker) Check(input, output, expected string) CheckResult {
+	got := splitLines(output)
+	want := splitLines(expected)
+
+	for i := 0; i < len(got) && i < len(want); i++ {
+		if got[i] != want[i] {
+			return CheckResult{VerdictWA, fmt.Sprintf("line %d differs: expected %q, found %q", i+1, want[i], got[i])}
+		}
+	}
+	if len(got) != len(want) {
+		return CheckResult{VerdictWA, fmt.Sprintf("expected %d line(s), found %d", len(want), len(got))}
+	}
+	return CheckResult{VerdictOK, fmt.Sprintf("%d line(s)", len(got))}
+}
+
+// splitLines splits text into lines without trailing whitespace, dropping trailing blank lines.
+func splitLines(text string) []string {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	for i := range lines {
+		lines[i] = strings.TrimRight(lines[i], " \t\r")
+	}
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// tokenChecker compares whitespace-separated tokens, optionally ignoring case.
+type tokenChecker struct {
+	ignoreCase bool
+}
+
+func (c tokenChecker) Check(input, output, expected string) CheckResult {
+	return compareTokens(output, expected, func(got, want string) bool {
+		if c.ignoreCase {
+			return strings.EqualFold(got, want)
+		}
+		return got == want
+	})
+}
+
+// floatChecker compares tokens, accepting numbers within an absolute or relative epsilon.
+type floatChecker struct {
+	epsilon float64
+}
+
+func (c floatChecker) Check(input, output, expected string) CheckResult {
+	return compareTokens(output, expected, func(got, want string) bool {
+		g, errG := strconv.ParseFloat(got, 64)
+		w, errW := strconv.ParseFloat(want, 64)
+		if errG != nil || errW != nil {
+			return got == want
+		}
+		diff := math.Abs(g - w)
+		return diff <= c.epsilon || diff <= c.epsilon*math.Abs(w)
+	})
+}
+
+// compareTokens walks both token streams and reports the first mismatch.
+func compareTokens(output, expected string, equal func(got, want string) bool) CheckResult {
+	got := strings.Fields(output)
+	want := strings.Fields(expected)
+
+	for i := 0; i < len(got) && i < len(want); i++ {
+		if !equal(got[i], want[i]) {
+			return CheckResult{VerdictWA, fmt.Sprintf("token %d differs: expected %q, found %q", i+1, want[i], got[i])}
+		}
+	}
+	if len(got) != len(want) {
+		return CheckResult{VerdictWA, fmt.Sprintf("expected %d token(s), found %d", len(want), len(got))}
+	}
+	return CheckResult{VerdictOK, fmt.Sprintf("%d token(s)", len(got))}
+}
+
+// programChecker runs an external checker with testlib's "checker input output answer" convention.
+type programChecker struct {
+	path string
+}
+
+// newProgramChecker prepares a checker program, compiling it if given as C++ source.
+func newProgramChecker(path string, quiet bool) (Checker, error) {
+	path, err := utils.PrepareProgram(path, quiet)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build checker: %w", err)
+	}
+	return programChecker{path: path}, nil
+}
+
+// testlib exit codes
+const (
+	testlibOK   = 0
+	testlibWA   = 1
+	testlibPE   = 2
+	testlibFail = 3
+)
+
+// testlibVerdict maps the exit code of a testlib checker or interactor to a verdict.
+func testlibVerdict(exitCode int, message string) CheckResult {
+	switch exitCode {
+	case testlibOK:
+		return CheckResult{VerdictOK, message}
+	case testlibWA:
+		return CheckResult{VerdictWA, message}
+	case testlibPE:
+		return CheckResult{VerdictPE, message}
+	case testlibFail:
+		return CheckResult{VerdictFail, message}
+	}
+	return CheckResult{VerdictFail, fmt.Sprintf("exited with unexpected code %d: %s", exitCode, message)}
+}
+
+// writeTestlibFiles writes the input, output and answer files passed to testlib programs
+// into a fresh temporary directory, which the caller must remove.
+func writeTestlibFiles(input, output, answer string) (string, []string, error) {
+	dir, err := os.MkdirTemp("", "fo-testlib-")
+	if err != nil {
+		return "", nil, err
+	}
+
+	files := []struct{ name, content string }{
+		{"input.txt", input},
+		{"output.txt", output},
+		{"answer.txt", answer},
+	}
+	var paths []string
+	for _, f := range files {
+		path := filepath.Join(dir, f.name)
+		if err := utils.WriteStringToFile(path, f.content); err != nil {
+			os.RemoveAll(dir)
+			return "", nil, err
+		}
+		paths = append(paths, path)
+	}
+	return dir, paths, nil
+}
+
+func (c programChecker) Check(input, output, expected string) CheckResult {
+	dir, args, err := writeTestlibFiles(input, output, expected)
+	if err != nil {
+		return CheckResult{VerdictFail, err.Error()}
+	}
+	defer os.RemoveAll(dir)
+
+	res, err := utils.RunWithLimits(context.Background(), utils.RunOptions{TimeLimit: helperTimeLimit}, c.path, args...)
+	if err != nil {
+		return CheckResult{VerdictFail, fmt.Sprintf("failed to run checker: %v", err)}
+	}
+	message := strings.TrimSpace(res.Stderr)
+	if res.TimedOut {
+		return CheckResult{VerdictFail, "checker timed out"}
+	}
+	if res.Signal != "" {
+		return CheckResult{VerdictFail, fmt.Sprintf("checker crashed with %s", res.Signal)}
+	}
+
+	return testlibVerdict(res.ExitCode, message)
+}
@@ -32,6 +32,29 @@ var cleanCmd = &cobra.Command{
 			fmt.Printf("%s'%s' executable%s not found, nothing to clean.%s\n", colors.YELLOW, utils.CmdConfig.ExecutableName, colors.RESET, colors.RESET)
 		}
 
+		// Remove the build manifest recorded next to the executable
+		if manifest := utils.ManifestPath(utils.CmdConfig.ExecutableName); utils.PathExists(manifest) {
+			if err := os.Remove(manifest); err != nil {
+				fmt.Fprintf(os.Stderr, "%s❌ Error removing '%s': %v%s\n", colors.RED, manifest, err, colors.RESET)
+				os.Exit(1)
+			}
+		}
+
+		// Remove the executables of build profiles, e.g. 'main-debug'
+		for _, profile := range utils.CmdConfig.Profiles {
+			executable := profile.ExecutableName
+			if executable == "" || executable == utils.CmdConfig.ExecutableName || !utils.PathExists(executable) {
+				continue
+			}
+			for _, file := range []string{executable, utils.ManifestPath(executable)} {
+				if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+					fmt.Fprintf(os.Stderr, "%s❌ Error removing '%s': %v%s\n", colors.RED, file, err, colors.RESET)
+					os.Exit(1)
+				}
+			}
+			fmt.Printf("%s✅ Removed '%s' executable.%s\n", colors.GREEN, executable, colors.RESET)
+		}
+
 		// Remove testcases.txt
 		if utils.PathExists("testcases.txt") {
 			err := os.Remove("testcases.txt")
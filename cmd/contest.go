@@ -0,0 +1,182 @@
+package cmd
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gocolly/colly/v2"
+	"github.com/spf13/cobra"
+
+	"github.com/ahmedYasserM/fo/internal/colors"
+)
+
+var (
+	contestJobs  int
+	contestDelay time.Duration
+)
+
+var contestCmd = &cobra.Command{
+	Use:   "contest <URL | ID>",
+	Short: "Sets up every problem of a Codeforces contest in its own directory",
+	Long: `Reads the problem list of a Codeforces contest and sets up a directory per problem
+(A, B, C1, C2, ...) in the current directory, like 'fo setup' does for a single problem:
+the samples, problem.yaml with the limits, and the source file from the template unless
+it already exists. Running it again retries the problems that failed and keeps your code.
+
+Problems are fetched by a few workers at once, starting one request per --delay so the
+judge is not flooded.
+
+Examples:
+  fo contest 1234
+  fo contest https://codeforces.com/contest/1234
+  fo contest https://codeforces.com/gym/102001 --lang python`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := preload(cmd); err != nil {
+			return err
+		}
+		if contestJobs < 1 {
+			return fmt.Errorf("--jobs must be at least 1")
+		}
+
+		contestURL, err := parseContestURL(args[0])
+		if err != nil {
+			return err
+		}
+		problems, err := scrapeContest(contestURL)
+		if err != nil {
+			return err
+		}
+		indexes := make([]string, len(problems))
+		for i, problem := range problems {
+			indexes[i] = problem.index
+		}
+		fmt.Printf("Found %d problem(s): %s%s%s\n", len(problems), colors.CYAN, strings.Join(indexes, ", "), colors.RESET)
+
+		failed := fetchContest(problems, contestJobs, contestDelay)
+		if len(failed) > 0 {
+			return fmt.Errorf("failed to set up %s, run the command again to retry", strings.Join(failed, ", "))
+		}
+		fmt.Printf("%s✅ Set up %d problem(s).%s\n", colors.GREEN, len(problems), colors.RESET)
+		return nil
+	},
+}
+
+var (
+	contestIDRegex = regexp.MustCompile(`^\d+$`)
+	// contestPathRegex matches the path of a contest or gym page, e.g. /contest/1234
+	contestPathRegex = regexp.MustCompile(`^/(contest|gym)/(\d+)`)
+)
+
+// parseContestURL returns the URL of the contest page for a contest URL or a bare contest ID.
+func parseContestURL(arg string) (string, error) {
+	if contestIDRegex.MatchString(arg) {
+		return "https://codeforces.com/contest/" + arg, nil
+	}
+
+	u, err := url.Parse(arg)
+	if err != nil || u.Host == "" {
+		return "", fmt.Errorf("invalid contest %q (expected a contest URL or ID)", arg)
+	}
+	matches := contestPathRegex.FindStringSubmatch(u.Path)
+	if matches == nil {
+		return "", fmt.Errorf("%s is not a contest page", arg)
+	}
+	return u.Scheme + "://" + u.Host + matches[0], nil
+}
+
+// contestProblem is an entry of the problem list of a contest.
+type contestProblem struct {
+	index string // e.g. A or C1, also the name of its directory
+	url   string
+}
+
+// scrapeContest reads the problem list from a Codeforces contest page.
+func scrapeContest(contestURL string) ([]contestProblem, error) {
+	c := newCollector()
+
+	var problems []contestProblem
+	c.OnHTML("table.problems td.id a", func(e *colly.HTMLElement) {
+		problems = append(problems, contestProblem{
+			index: strings.TrimSpace(e.Text),
+			url:   e.Request.AbsoluteURL(e.Attr("href")),
+		})
+	})
+
+	if err := c.Visit(contestURL); err != nil {
+		return nil, fmt.Errorf("failed to visit URL %w", err)
+	}
+	if len(problems) == 0 {
+		return nil, fmt.Errorf("no problems found on %s, has the contest started?", contestURL)
+	}
+	return problems, nil
+}
+
+// fetchContest sets up the problems on jobs workers, starting at most one fetch per delay.
+// It returns the indexes of the problems that failed.
+func fetchContest(problems []contestProblem, jobs int, delay time.Duration) []string {
+	var limiter <-chan time.Time
+	if delay > 0 {
+		ticker := time.NewTicker(delay)
+		defer ticker.Stop()
+		limiter = ticker.C
+	}
+
+	work := make(chan contestProblem)
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		failed = map[string]bool{}
+	)
+	for range min(jobs, len(problems)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for problem := range work {
+				if limiter != nil {
+					<-limiter
+				}
+				if err := setupContestProblem(problem); err != nil {
+					fmt.Printf("%s❌ %s: %v%s\n", colors.RED, problem.index, err, colors.RESET)
+					mu.Lock()
+					failed[problem.index] = true
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	for _, problem := range problems {
+		work <- problem
+	}
+	close(work)
+	wg.Wait()
+
+	// Report failures in contest order rather than completion order
+	var indexes []string
+	for _, problem := range problems {
+		if failed[problem.index] {
+			indexes = append(indexes, problem.index)
+		}
+	}
+	return indexes
+}
+
+// setupContestProblem fetches a problem into its directory and creates its source file.
+func setupContestProblem(problem contestProblem) error {
+	fetched, tests, err := scrapeProblem(problem.url)
+	if err != nil {
+		return err
+	}
+	return setupProblemDir(problem.index, fetched, tests)
+}
+
+func init() {
+	contestCmd.Flags().IntVarP(&contestJobs, "jobs", "j", 4, "Number of problems fetched at once")
+	contestCmd.Flags().DurationVar(&contestDelay, "delay", 500*time.Millisecond, "Minimum time between two requests to the judge")
+	addLangFlag(contestCmd)
+	rootCmd.AddCommand(contestCmd)
+}
@@ -0,0 +1,298 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/ahmedYasserM/fo/internal/colors"
+	"github.com/ahmedYasserM/fo/internal/utils"
+)
+
+const (
+	// diffContext is the number of equal lines shown around each difference.
+	diffContext = 3
+	// sideWidth is the width of each column of a side-by-side diff.
+	sideWidth = 38
+)
+
+// displayOptions controls how the details of failing tests are shown.
+type displayOptions struct {
+	DiffMode string // "unified" or "side"
+	Limit    int    // maximum number of lines shown per block, 0 for no limit
+}
+
+// defaultDisplay returns the display options from the config.
+func defaultDisplay() displayOptions {
+	return displayOptions{DiffMode: utils.CmdConfig.DiffMode, Limit: utils.CmdConfig.DisplayLimit}
+}
+
+// printTruncated prints text, cutting it after limit lines.
+func printTruncated(text string, limit int) {
+	lines := strings.Split(text, "\n")
+	if limit > 0 && len(lines) > limit {
+		fmt.Println(strings.Join(lines[:limit], "\n"))
+		fmt.Printf("%s... %d more line(s) hidden, use --full to see everything%s\n", colors.CYAN, len(lines)-limit, colors.RESET)
+		return
+	}
+	fmt.Println(text)
+}
+
+// printDiff shows where the output differs from the expected output, as a unified or
+// side-by-side diff of the lines around each difference. Lines are aligned along their
+// longest common subsequence, so a missing or extra line only marks itself as changed.
+func printDiff(output, expected string, display displayOptions) {
+	got := splitLines(output)
+	want := splitLines(expected)
+	rows := alignLines(got, want)
+
+	first := slices.IndexFunc(rows, func(row diffRow) bool { return !row.same })
+	if first < 0 {
+		// The lines match, so the difference is in the whitespace layout
+		fmt.Printf("%sYour output:%s\n", colors.YELLOW, colors.RESET)
+		printTruncated(strings.TrimSpace(output), display.Limit)
+		fmt.Printf("%sExpected:%s\n", colors.YELLOW, colors.RESET)
+		printTruncated(strings.TrimSpace(expected), display.Limit)
+		return
+	}
+
+	// A changed line is compared token by token with the line it replaces, if any
+	token := -1
+	if rows[first].got >= 0 && rows[first].want >= 0 {
+		token = firstDifferentToken(got[rows[first].got], want[rows[first].want])
+		fmt.Printf("%sFirst difference at line %d, token %d%s\n", colors.YELLOW, rows[first].got+1, token+1, colors.RESET)
+	} else if rows[first].got >= 0 {
+		fmt.Printf("%sFirst difference at line %d: extra line in your output%s\n", colors.YELLOW, rows[first].got+1, colors.RESET)
+	} else {
+		fmt.Printf("%sFirst difference at line %d: missing line in your output%s\n", colors.YELLOW, rows[first].want+1, colors.RESET)
+	}
+
+	// Show every row within diffContext rows of a difference
+	shown := make([]bool, len(rows))
+	for i, row := range rows {
+		if !row.same {
+			for j := max(0, i-diffContext); j <= min(len(rows)-1, i+diffContext); j++ {
+				shown[j] = true
+			}
+		}
+	}
+
+	if display.DiffMode == "side" {
+		fmt.Printf("%s%4s  %-*s │ %4s  %s%s\n", colors.BOLD, "line", sideWidth, "your output", "line", "expected", colors.RESET)
+	} else {
+		fmt.Printf("%s--- expected%s\n%s+++ your output%s\n", colors.GREEN, colors.RESET, colors.RED, colors.RESET)
+	}
+
+	// gotLine and wantLine are the line numbers the next row starts at in each text
+	gotLine, wantLine := 1, 1
+	printed := 0
+	for i, row := range rows {
+		rowGot, rowWant := gotLine, wantLine
+		if row.got >= 0 {
+			gotLine++
+		}
+		if row.want >= 0 {
+			wantLine++
+		}
+		if !shown[i] {
+			continue
+		}
+		if display.Limit > 0 && printed >= display.Limit {
+			fmt.Printf("%s... more differences hidden, use --full to see everything%s\n", colors.CYAN, colors.RESET)
+			return
+		}
+		printed++
+		if i == 0 || !shown[i-1] {
+			fmt.Printf("%s@@ -%d +%d @@%s\n", colors.CYAN, rowWant, rowGot, colors.RESET)
+		}
+
+		// Underline the first differing token, then restore the line color
+		line := func(lines []string, index int, color string) string {
+			if index < 0 {
+				return ""
+			}
+			if i == first && token >= 0 {
+				return highlightToken(lines[index], token, color)
+			}
+			return lines[index]
+		}
+
+		switch {
+		case display.DiffMode == "side":
+			color := ""
+			if !row.same {
+				color = colors.RED
+			}
+			fmt.Printf("%4s  %s%s%s │ %4s  %s%s%s\n", lineNumber(row.got), color, fitColumn(line(got, row.got, color)), colors.RESET,
+				lineNumber(row.want), color, line(want, row.want, color), colors.RESET)
+		case row.same:
+			fmt.Printf("  %s\n", got[row.got])
+		default:
+			if row.want >= 0 {
+				fmt.Printf("%s- %s%s\n", colors.GREEN, line(want, row.want, colors.GREEN), colors.RESET)
+			}
+			if row.got >= 0 {
+				fmt.Printf("%s+ %s%s\n", colors.RED, line(got, row.got, colors.RED), colors.RESET)
+			}
+		}
+	}
+}
+
+// lineNumber formats the 1-based number of the line at index, blank when there is none.
+func lineNumber(index int) string {
+	if index < 0 {
+		return ""
+	}
+	return strconv.Itoa(index + 1)
+}
+
+// diffRow is a line of the output shown next to a line of the expected output.
+// got or want is -1 when the other text has no line there.
+type diffRow struct {
+	got, want int
+	same      bool
+}
+
+// maxDiffEdits bounds the number of inserted and deleted lines looked for when aligning
+// two texts; beyond it, outputs are compared line by line.
+const maxDiffEdits = 1000
+
+// alignLines lines up the output with the expected output along their longest common
+// subsequence of lines. The lines between two common ones are paired up as changed.
+func alignLines(got, want []string) []diffRow {
+	prefix := 0
+	for prefix < len(got) && prefix < len(want) && got[prefix] == want[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(got)-prefix && suffix < len(want)-prefix && got[len(got)-1-suffix] == want[len(want)-1-suffix] {
+		suffix++
+	}
+
+	var rows []diffRow
+	for i := range prefix {
+		rows = append(rows, diffRow{got: i, want: i, same: true})
+	}
+	gotEnd, wantEnd := len(got)-suffix, len(want)-suffix
+	// Past maxDiffEdits nothing is common, and the rest is compared line by line
+	common, _ := commonLines(got[prefix:gotEnd], want[prefix:wantEnd])
+	gotNext, wantNext := prefix, prefix
+	for _, pair := range append(common, [2]int{gotEnd - prefix, wantEnd - prefix}) {
+		g, w := prefix+pair[0], prefix+pair[1]
+		for k := range max(g-gotNext, w-wantNext) {
+			row := diffRow{got: -1, want: -1}
+			if gotNext+k < g {
+				row.got = gotNext + k
+			}
+			if wantNext+k < w {
+				row.want = wantNext + k
+			}
+			rows = append(rows, row)
+		}
+		if g < gotEnd {
+			rows = append(rows, diffRow{got: g, want: w, same: true})
+		}
+		gotNext, wantNext = g+1, w+1
+	}
+	for i := range suffix {
+		rows = append(rows, diffRow{got: gotEnd + i, want: wantEnd + i, same: true})
+	}
+	return rows
+}
+
+// commonLines returns the index pairs of a longest common subsequence of the lines of
+// a and b, found with Myers' diff algorithm. It gives up, returning false, when the
+// texts differ by more than maxDiffEdits inserted and deleted lines.
+func commonLines(a, b []string) ([][2]int, bool) {
+	n, m := len(a), len(b)
+	limit := min(n+m, maxDiffEdits)
+	// v[offset+k] is the furthest x reached on diagonal k = x - y
+	offset := limit + 1
+	v := make([]int, 2*limit+3)
+	var trace [][]int
+
+	for d := 0; d <= limit; d++ {
+		trace = append(trace, slices.Clone(v))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				return backtrackCommon(trace, offset, n, m), true
+			}
+		}
+	}
+	return nil, false
+}
+
+// backtrackCommon walks the furthest points reached before each edit back from the end,
+// collecting the common lines passed on the way.
+func backtrackCommon(trace [][]int, offset, x, y int) [][2]int {
+	var pairs [][2]int
+	for d := len(trace) - 1; d >= 0; d-- {
+		v := trace[d]
+		k := x - y
+		prevK := k - 1
+		if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+			prevK = k + 1
+		}
+		prevX := v[offset+prevK]
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			x--
+			y--
+			pairs = append(pairs, [2]int{x, y})
+		}
+		x, y = prevX, prevY
+	}
+	slices.Reverse(pairs)
+	return pairs
+}
+
+// firstDifferentToken returns the index of the first token that differs between two lines.
+func firstDifferentToken(got, want string) int {
+	g, w := strings.Fields(got), strings.Fields(want)
+	for i := 0; i < len(g) && i < len(w); i++ {
+		if g[i] != w[i] {
+			return i
+		}
+	}
+	return min(len(g), len(w))
+}
+
+// highlightToken underlines the token at index in line, if it exists,
+// switching back to color afterwards.
+func highlightToken(line string, index int, color string) string {
+	tokens := strings.Fields(line)
+	if index >= len(tokens) {
+		return line
+	}
+	tokens[index] = colors.UNDERLINE + tokens[index] + colors.RESET + color
+	return strings.Join(tokens, " ")
+}
+
+// fitColumn pads or cuts a line to the side-by-side column width.
+// Escape sequences are not counted towards the width.
+func fitColumn(line string) string {
+	visible := len([]rune(stripEscapes(line)))
+	if visible > sideWidth {
+		runes := []rune(stripEscapes(line))
+		return string(runes[:sideWidth-1]) + "…"
+	}
+	return line + strings.Repeat(" ", sideWidth-visible)
+}
+
+// stripEscapes removes the color escape sequences added by highlightToken.
+func stripEscapes(s string) string {
+	return strings.NewReplacer(colors.UNDERLINE, "", colors.RESET, "", colors.RED, "").Replace(s)
+}
@@ -1,9 +1,14 @@
 package cmd
 
 import (
+	"cmp"
 	"fmt"
 	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/PuerkitoBio/goquery"
 	"github.com/gocolly/colly/v2"
@@ -11,19 +16,35 @@ import (
 	"golang.org/x/net/html"
 
 	"github.com/ahmedYasserM/fo/internal/colors"
+	"github.com/ahmedYasserM/fo/internal/utils"
 )
 
 var fetchCmd = &cobra.Command{
-	Use:   "fetch [URL]",
-	Short: "Fetch sample test cases from a Codeforces problem URL",
-	Long: `Fetch downloads sample input and output from a given Codeforces problem URL.
-The samples are saved to 'testcases.txt'.
-
-Example:
-  fo fetch https://codeforces.com/contest/1234/problem/A`,
+	Use:   "fetch <URL | ID>",
+	Short: "Fetch sample test cases from a problem URL",
+	Long: `Fetch downloads sample input and output from a given problem URL of Codeforces,
+AtCoder, CSES or Kattis; the judge is picked from the host of the URL.
+Short problem IDs work too: 799A or 799/A and gym:102001K for Codeforces,
+abc300_d for AtCoder, cses:1068 and kattis:hello.
+The samples are saved in the layout selected by 'test_format' in the config
+(default: 'testcases.txt'), and the limits and other metadata in problem.yaml.
+
+Examples:
+  fo fetch https://codeforces.com/contest/1234/problem/A
+  fo fetch 1234A
+  fo fetch https://atcoder.jp/contests/abc300/tasks/abc300_d
+  fo fetch https://cses.fi/problemset/task/1068
+  fo fetch https://open.kattis.com/problems/hello`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return fetchSamples(args[0])
+		if err := utils.LoadConfigOnce(false); err != nil {
+			return err
+		}
+		rawurl, err := resolveProblem(args[0])
+		if err != nil {
+			return err
+		}
+		return fetchSamples(rawurl)
 	},
 }
 
@@ -74,55 +95,110 @@ func extractNodeTextWithBr(n *html.Node, builder *strings.Builder) {
 	}
 }
 
-func fetchSamples(rawurl string) error {
-	c := colly.NewCollector(
-		colly.UserAgent("Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 " +
-			"(KHTML, like Gecko) Chrome/115.0.0.0 Safari/537.36"),
-	)
+var (
+	// Judges state limits as "2 seconds", "2 sec" or "1.00 s", and "256 megabytes" or "1024 MB"
+	timeLimitRegex   = regexp.MustCompile(`([\d.]+)\s*(?:seconds?|secs?|s)\b`)
+	memoryLimitRegex = regexp.MustCompile(`(\d+)\s*(?:megabytes?|MB|MiB)\b`)
+)
 
-	var inputs []string
-	var outputs []string
+// parseTimeLimit converts a statement header like "2 seconds" into a duration.
+func parseTimeLimit(text string) (time.Duration, bool) {
+	matches := timeLimitRegex.FindStringSubmatch(text)
+	if matches == nil {
+		return 0, false
+	}
+	seconds, err := strconv.ParseFloat(matches[1], 64)
+	if err != nil {
+		return 0, false
+	}
+	return time.Duration(seconds * float64(time.Second)), true
+}
 
-	c.OnHTML("div.sample-test", func(e *colly.HTMLElement) {
-		fmt.Println("Found sample-test block on the page") // Debug print
+// parseMemoryLimit converts a statement header like "256 megabytes" into megabytes.
+func parseMemoryLimit(text string) (int, bool) {
+	matches := memoryLimitRegex.FindStringSubmatch(text)
+	if matches == nil {
+		return 0, false
+	}
+	megabytes, err := strconv.Atoi(matches[1])
+	if err != nil {
+		return 0, false
+	}
+	return megabytes, true
+}
 
-		e.ForEach("div.input", func(idx int, el *colly.HTMLElement) {
-			el.DOM.Find("pre").Each(func(_ int, pre *goquery.Selection) {
-				text := strings.TrimSpace(extractPreText(pre))
-				fmt.Printf("Sample input #%d:\n%s\n---\n", idx+1, text) // Debug print
-				inputs = append(inputs, text)
-			})
-		})
+func fetchSamples(rawurl string) error {
+	problem, tests, err := scrapeProblem(rawurl)
+	if err != nil {
+		return err
+	}
+	for i, test := range tests {
+		fmt.Printf("Sample input #%d:\n%s\n---\n", i+1, test.Input)
+		fmt.Printf("Sample output #%d:\n%s\n---\n", i+1, test.Expected)
+	}
 
-		e.ForEach("div.output", func(idx int, el *colly.HTMLElement) {
-			el.DOM.Find("pre").Each(func(_ int, pre *goquery.Selection) {
-				text := strings.TrimSpace(extractPreText(pre))
-				fmt.Printf("Sample output #%d:\n%s\n---\n", idx+1, text) // Debug print
-				outputs = append(outputs, text)
-			})
-		})
-	})
+	source, saved, err := saveProblem(".", problem, tests)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s✅ Saved %d sample(s) to %s%s\n", colors.GREEN, len(tests), source.Name(), colors.RESET)
+	fmt.Printf("%s✅ Saved problem metadata to %s%s\n", colors.GREEN, utils.ProblemFile, colors.RESET)
+	fmt.Printf("   %s (time limit %s, memory limit %d MB", cmp.Or(saved.Title, "untitled"), saved.TimeLimit, saved.MemoryLimit)
+	if saved.InputFile != "" || saved.OutputFile != "" {
+		fmt.Printf(", input %s, output %s", cmp.Or(saved.InputFile, "stdin"), cmp.Or(saved.OutputFile, "stdout"))
+	}
+	fmt.Println(")")
+	return nil
+}
 
-	err := c.Visit(rawurl)
+// newCollector returns a collector identifying as a regular browser.
+func newCollector() *colly.Collector {
+	return colly.NewCollector(
+		colly.UserAgent("Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 " +
+			"(KHTML, like Gecko) Chrome/115.0.0.0 Safari/537.36"),
+	)
+}
+
+// saveProblem writes fetched samples in the configured layout and the metadata to problem.yaml
+// in dir. Settings such as the checker in an existing problem.yaml are kept.
+func saveProblem(dir string, problem *utils.Problem, tests []Testcase) (TestSource, *utils.Problem, error) {
+	source, err := configuredTestSourceIn(dir)
 	if err != nil {
-		return fmt.Errorf("failed to visit URL %w", err)
+		return nil, nil, err
+	}
+	if err := source.Save(tests); err != nil {
+		return nil, nil, err
 	}
 
-	if len(inputs) == 0 || len(inputs) != len(outputs) {
-		return fmt.Errorf("could not find matching sample inputs and outputs")
+	saved, err := utils.LoadProblemIn(dir)
+	if err != nil {
+		return nil, nil, err
 	}
+	saved.Merge(problem)
+	if err := utils.SaveProblemIn(dir, saved); err != nil {
+		return nil, nil, fmt.Errorf("failed to write %s: %w", utils.ProblemFile, err)
+	}
+	return source, saved, nil
+}
 
-	f, err := os.Create("testcases.txt")
+// setupProblemDir saves a fetched problem into its own directory dir, creates the source
+// file from the template unless it exists, and prints a one-line summary.
+func setupProblemDir(dir string, problem *utils.Problem, tests []Testcase) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	_, saved, err := saveProblem(dir, problem, tests)
 	if err != nil {
-		return fmt.Errorf("failed to create testcases.txt: %w", err)
+		return err
 	}
-	defer f.Close()
 
-	for i := range inputs {
-		fmt.Fprintf(f, "--- Sample #%d Input ---\n%s\n\n", i+1, inputs[i])
-		fmt.Fprintf(f, "--- Sample #%d Output ---\n%s\n\n", i+1, outputs[i])
+	source := filepath.Join(dir, utils.CmdConfig.SourceName)
+	if !utils.PathExists(source) {
+		if err := utils.WriteStringToFile(source, utils.CmdTemplate); err != nil {
+			return fmt.Errorf("failed to create %s: %w", source, err)
+		}
 	}
 
-	fmt.Printf("%s✅ Saved %d sample(s) to testcases.txt%s\n", colors.GREEN, len(inputs), colors.RESET)
+	fmt.Printf("%s✅ %s: %s%s (%d sample(s), time limit %s, memory limit %d MB)\n", colors.GREEN, dir, saved.Title, colors.RESET, len(tests), saved.TimeLimit, saved.MemoryLimit)
 	return nil
 }
@@ -0,0 +1,216 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ahmedYasserM/fo/internal/colors"
+	"github.com/ahmedYasserM/fo/internal/utils"
+)
+
+// companionPort is the port Competitive Companion sends problems to for JHelper and Caide,
+// one of those enabled in the extension by default.
+const companionPort = 10043
+
+var listenPort int
+
+var listenCmd = &cobra.Command{
+	Use:   "listen",
+	Short: "Receives problems from the Competitive Companion browser extension",
+	Long: `Starts a local server receiving the problems parsed by the Competitive Companion
+browser extension (https://github.com/jmerle/competitive-companion), which works on
+judges that block scrapers or require a login.
+
+Every problem received is set up in a directory of its own in the current directory,
+named after its index (A, B, C1, ...), like 'fo setup' does for a URL: its samples,
+problem.yaml with the limits, and the source file from the template unless it already
+exists. Parsing a whole contest in the extension sends all of its problems at once.
+
+Stop the server with Ctrl+C.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := preload(cmd); err != nil {
+			return err
+		}
+
+		receiver := &companionReceiver{batches: map[string]int{}}
+		server := &http.Server{
+			Addr:              fmt.Sprintf("127.0.0.1:%d", listenPort),
+			Handler:           receiver,
+			ReadHeaderTimeout: 5 * time.Second,
+		}
+		fmt.Printf("%sListening for Competitive Companion on port %d, press Ctrl+C to stop...%s\n", colors.CYAN, listenPort, colors.RESET)
+		return server.ListenAndServe()
+	},
+}
+
+// companionProblem is the problem format sent by Competitive Companion.
+type companionProblem struct {
+	Name        string `json:"name"`
+	Group       string `json:"group"` // judge and contest, e.g. "Codeforces - Round 413"
+	URL         string `json:"url"`
+	Interactive bool   `json:"interactive"`
+	MemoryLimit int    `json:"memoryLimit"` // in megabytes
+	TimeLimit   int    `json:"timeLimit"`   // in milliseconds
+	Tests       []struct {
+		Input  string `json:"input"`
+		Output string `json:"output"`
+	} `json:"tests"`
+	Input  companionIO `json:"input"`
+	Output companionIO `json:"output"`
+	Batch  struct {
+		ID   string `json:"id"`
+		Size int    `json:"size"`
+	} `json:"batch"`
+}
+
+// companionIO tells whether a problem uses standard I/O or files.
+type companionIO struct {
+	Type     string `json:"type"` // stdin, stdout, file or regex
+	FileName string `json:"fileName"`
+}
+
+// companionReceiver sets up the problems posted to it, one at a time.
+type companionReceiver struct {
+	mu      sync.Mutex
+	batches map[string]int // problems received per batch
+}
+
+func (r *companionReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(w, "expected a POST request from Competitive Companion", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Any page open in the browser can post here too, but only with a body type
+	// that needs no preflight, which Competitive Companion never sends
+	if mediaType, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type")); mediaType != "application/json" {
+		http.Error(w, "expected a JSON problem from Competitive Companion", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	payloads, err := decodeCompanion(http.MaxBytesReader(w, req.Body, 64<<20))
+	if err != nil {
+		fmt.Printf("%s❌ Invalid problem received: %v%s\n", colors.RED, err, colors.RESET)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, payload := range payloads {
+		if err := setupCompanionProblem(&payload); err != nil {
+			fmt.Printf("%s❌ %s: %v%s\n", colors.RED, payload.Name, err, colors.RESET)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// Contests arrive as one request per problem, sharing a batch ID
+		if payload.Batch.Size > 1 {
+			r.batches[payload.Batch.ID]++
+			if received := r.batches[payload.Batch.ID]; received == payload.Batch.Size {
+				fmt.Printf("%s✅ Received all %d problems of %s.%s\n", colors.GREEN, received, payload.Group, colors.RESET)
+				delete(r.batches, payload.Batch.ID)
+			}
+		}
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// decodeCompanion reads a single problem, or a list of problems sent at once.
+func decodeCompanion(body io.Reader) ([]companionProblem, error) {
+	var raw json.RawMessage
+	if err := json.NewDecoder(body).Decode(&raw); err != nil {
+		return nil, err
+	}
+	if trimmed := bytes.TrimSpace(raw); len(trimmed) > 0 && trimmed[0] == '[' {
+		var payloads []companionProblem
+		err := json.Unmarshal(raw, &payloads)
+		return payloads, err
+	}
+	var payload companionProblem
+	err := json.Unmarshal(raw, &payload)
+	return []companionProblem{payload}, err
+}
+
+var (
+	// Problem names start with their index, e.g. "C1. Pokémon Army" or "D - AABCC"
+	companionIndexRegex = regexp.MustCompile(`^([A-Za-z][0-9]*)(?:\.|\s+-)\s+`)
+	unsafeNameRegex     = regexp.MustCompile(`[^\w-]+`)
+)
+
+// companionDir returns the directory of a problem: its index when its name has one,
+// the problem ID from its URL otherwise, or else its name made safe for paths.
+func companionDir(payload *companionProblem, problemID string) string {
+	if matches := companionIndexRegex.FindStringSubmatch(payload.Name); matches != nil {
+		return strings.ToUpper(matches[1])
+	}
+	if problemID != "" {
+		return problemID
+	}
+	name := strings.Trim(unsafeNameRegex.ReplaceAllString(strings.ToLower(payload.Name), "-"), "-")
+	if name == "" {
+		return "problem"
+	}
+	return name
+}
+
+// setupCompanionProblem creates the directory of a received problem with its tests,
+// problem.yaml and source file.
+func setupCompanionProblem(payload *companionProblem) error {
+	problem := &utils.Problem{
+		Title:       companionIndexRegex.ReplaceAllString(payload.Name, ""),
+		URL:         payload.URL,
+		TimeLimit:   time.Duration(payload.TimeLimit) * time.Millisecond,
+		MemoryLimit: payload.MemoryLimit,
+	}
+	if u, err := url.Parse(payload.URL); err == nil {
+		if provider, err := providerFor(u); err == nil {
+			problem.ContestID, problem.ProblemID = provider.IDs(u)
+		}
+	}
+	if payload.Input.Type == "file" {
+		if err := utils.CheckIOFile(payload.Input.FileName); err != nil {
+			return err
+		}
+		problem.InputFile = payload.Input.FileName
+	}
+	if payload.Output.Type == "file" {
+		if err := utils.CheckIOFile(payload.Output.FileName); err != nil {
+			return err
+		}
+		problem.OutputFile = payload.Output.FileName
+	}
+
+	tests := make([]Testcase, len(payload.Tests))
+	for i, test := range payload.Tests {
+		tests[i] = Testcase{Input: strings.TrimSpace(test.Input), Expected: strings.TrimSpace(test.Output)}
+	}
+
+	dir := companionDir(payload, problem.ProblemID)
+	if err := setupProblemDir(dir, problem, tests); err != nil {
+		return err
+	}
+	if payload.Interactive {
+		fmt.Printf("%s⚠️ %s is interactive, add its interactor to %s before testing.%s\n", colors.YELLOW, dir, filepath.Join(dir, utils.ProblemFile), colors.RESET)
+	}
+	return nil
+}
+
+func init() {
+	listenCmd.Flags().IntVar(&listenPort, "port", companionPort, "Port Competitive Companion sends problems to")
+	addLangFlag(listenCmd)
+	rootCmd.AddCommand(listenCmd)
+}
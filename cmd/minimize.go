@@ -0,0 +1,360 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ahmedYasserM/fo/internal/colors"
+	"github.com/ahmedYasserM/fo/internal/utils"
+
+	"github.com/spf13/cobra"
+)
+
+// maxMinimizeAttempts bounds how many candidate inputs are tried while minimizing.
+const maxMinimizeAttempts = 2000
+
+var (
+	minimizeQuiet bool
+	minimizeBrute string
+)
+
+var minimizeCmd = &cobra.Command{
+	Use:   "minimize [test number]",
+	Short: "Shrinks a failing test case while it still fails against a brute force solution",
+	Long: `Takes a test case from the tests (default: the last one) that the solution fails,
+and repeatedly tries to shrink it by removing lines and tokens and making numbers smaller,
+keeping every candidate on which the solution still disagrees with the brute force
+solution (default: brute.cpp). Counts such as 'n' that describe the length of a
+following line or block of lines are kept consistent.
+
+The minimized input is appended to the tests as a new test case.
+
+Example:
+  fo minimize 3`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := utils.LoadConfigOnce(minimizeQuiet); err != nil {
+			return err
+		}
+
+		_, tests, err := loadTests()
+		if err != nil {
+			return err
+		}
+		index := len(tests)
+		if len(args) == 1 {
+			if index, err = parseTestNumber(args[0], len(tests)); err != nil {
+				return err
+			}
+		}
+
+		runner, brute, err := prepareBruteRun(cmd, minimizeBrute, minimizeQuiet)
+		if err != nil {
+			return err
+		}
+		fails := failsAgainstBrute(runner, brute)
+		if !fails(tests[index-1].Input) {
+			return fmt.Errorf("the solution agrees with the brute force solution on test #%d, nothing to minimize", index)
+		}
+
+		return minimizeAndSave(runner, brute, tests[index-1].Input)
+	},
+}
+
+// prepareBruteRun builds the solution and the brute force solution and sets up a runner
+// using the limits and checker selected by the flags of cmd.
+func prepareBruteRun(cmd *cobra.Command, brutePath string, quiet bool) (*testRunner, string, error) {
+	if err := ensureBuilt(quiet); err != nil {
+		return nil, "", err
+	}
+	brute, err := utils.PrepareProgram(brutePath, quiet)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to build brute force solution: %w", err)
+	}
+
+	timeLimit, memoryLimit, err := resolveLimits(cmd)
+	if err != nil {
+		return nil, "", err
+	}
+	if utils.MemoryLimitUnsupported() != "" {
+		memoryLimit = 0
+	}
+	checker, err := resolveChecker(cmd, quiet)
+	if err != nil {
+		return nil, "", err
+	}
+
+	runner := &testRunner{
+		opts: utils.RunOptions{
+			TimeLimit:   timeLimit,
+			MemoryLimit: int64(memoryLimit) << 20,
+			OutputLimit: int64(utils.CmdConfig.OutputLimit) << 20,
+		},
+		checker: checker,
+	}
+	return runner, brute, nil
+}
+
+// failsAgainstBrute returns a predicate reporting whether the solution fails on an input
+// that the brute force solution handles without errors.
+func failsAgainstBrute(runner *testRunner, brute string) func(string) bool {
+	return func(input string) bool {
+		expected, err := runHelper(brute, input)
+		if err != nil {
+			return false
+		}
+		result := runner.run(context.Background(), Testcase{Input: input, Expected: expected})
+		return result.Err == nil && !result.Verdict.Passed()
+	}
+}
+
+// minimizeAndSave minimizes a failing input, reports the result and appends it to the tests.
+func minimizeAndSave(runner *testRunner, brute, input string) error {
+	fmt.Printf("%sMinimizing a failing input of %d bytes...%s\n", colors.CYAN, len(input), colors.RESET)
+	m := &minimizer{fails: failsAgainstBrute(runner, brute)}
+	minimized := m.minimize(input)
+	fmt.Printf("%sMinimized to %d bytes after %d attempts.%s\n", colors.CYAN, len(minimized), m.attempts, colors.RESET)
+
+	expected, err := runHelper(brute, minimized)
+	if err != nil {
+		return fmt.Errorf("brute force solution failed on the minimized input: %w", err)
+	}
+	test := Testcase{Input: minimized, Expected: expected}
+	source, err := detectTestSource()
+	if err != nil {
+		return err
+	}
+	index, err := appendTestcase(source, test)
+	if err != nil {
+		return err
+	}
+
+	printTestResult(index, test, runner.run(context.Background(), test), defaultDisplay())
+	fmt.Printf("%s✅ Saved the minimized input as test #%d in %s%s\n", colors.GREEN, index, source.Name(), colors.RESET)
+	return nil
+}
+
+// minimizer greedily shrinks an input while a predicate keeps reporting it as failing.
+// The input is treated as lines of whitespace-separated tokens.
+type minimizer struct {
+	fails    func(input string) bool
+	attempts int
+}
+
+// minimize returns a locally minimal failing input: no single line removal, token
+// removal or number shrink keeps it failing (or the attempt budget ran out).
+func (m *minimizer) minimize(input string) string {
+	lines := tokenizeInput(input)
+
+	for changed := true; changed && m.attempts < maxMinimizeAttempts; {
+		changed = false
+
+		// Drop whole lines, from the bottom up
+		for r := len(lines) - 1; r >= 0 && r < len(lines); r-- {
+			if m.try(&lines, removeLine(lines, r)) {
+				changed = true
+			}
+		}
+
+		// Drop chunks of tokens, halving the chunk size down to single tokens
+		for r := range lines {
+			for size := len(lines[r]) / 2; size >= 1; size /= 2 {
+				for start := 0; start+size <= len(lines[r]); {
+					if !m.try(&lines, removeTokens(lines, r, start, size)) {
+						start += size
+					} else {
+						changed = true
+					}
+				}
+			}
+		}
+
+		// Make numbers smaller, leaving counts alone
+		for r := range lines {
+			for c := range lines[r] {
+				if isCountToken(lines, r, c) {
+					continue
+				}
+				for _, smaller := range smallerNumbers(lines[r][c]) {
+					if m.try(&lines, replaceToken(lines, r, c, smaller)) {
+						changed = true
+						break
+					}
+				}
+			}
+		}
+	}
+	return renderInput(lines)
+}
+
+// try adopts the candidate if it still fails.
+func (m *minimizer) try(lines *[][]string, candidate [][]string) bool {
+	if candidate == nil || m.attempts >= maxMinimizeAttempts {
+		return false
+	}
+	m.attempts++
+	if !m.fails(renderInput(candidate)) {
+		return false
+	}
+	*lines = candidate
+	return true
+}
+
+func tokenizeInput(input string) [][]string {
+	var lines [][]string
+	for _, line := range strings.Split(strings.TrimSpace(input), "\n") {
+		lines = append(lines, strings.Fields(line))
+	}
+	return lines
+}
+
+func renderInput(lines [][]string) string {
+	var b strings.Builder
+	for _, line := range lines {
+		b.WriteString(strings.Join(line, " "))
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+func cloneLines(lines [][]string) [][]string {
+	clone := make([][]string, len(lines))
+	for i, line := range lines {
+		clone[i] = append([]string(nil), line...)
+	}
+	return clone
+}
+
+// findCount looks for an integer token equal to value in the lines before row,
+// nearest first, which most likely is the count describing what follows.
+func findCount(lines [][]string, row, value int) (int, int, bool) {
+	for r := row - 1; r >= 0; r-- {
+		for c, token := range lines[r] {
+			if n, err := strconv.Atoi(token); err == nil && n == value {
+				return r, c, true
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+// adjustCount decrements the count token equal to value before row, if any.
+func adjustCount(lines [][]string, row, value, by int) {
+	if r, c, ok := findCount(lines, row, value); ok {
+		lines[r][c] = strconv.Itoa(value - by)
+	}
+}
+
+// blockBounds returns the range of consecutive lines around row having as many tokens as row.
+func blockBounds(lines [][]string, row int) (int, int) {
+	start := row
+	for start > 0 && len(lines[start-1]) == len(lines[row]) {
+		start--
+	}
+	return start, blockEnd(lines, row)
+}
+
+// blockEnd returns the end of the run of lines starting at row having as many tokens as row.
+func blockEnd(lines [][]string, row int) int {
+	end := row + 1
+	for end < len(lines) && len(lines[end]) == len(lines[row]) {
+		end++
+	}
+	return end
+}
+
+// removeLine drops a line, decrementing the count of its block of similar lines.
+// Lines holding counts, and lone lines whose length is given by a count, are kept;
+// their tokens are removed instead.
+func removeLine(lines [][]string, row int) [][]string {
+	if len(lines) <= 1 {
+		return nil
+	}
+	for c := range lines[row] {
+		if isCountToken(lines, row, c) {
+			return nil
+		}
+	}
+
+	candidate := cloneLines(lines)
+	start, end := blockBounds(lines, row)
+	// A count on its own line looks like part of the block it describes
+	for start < row && isCountToken(lines, start, 0) {
+		start++
+	}
+	if end-start > 1 {
+		adjustCount(candidate, start, end-start, 1)
+	} else if _, _, ok := findCount(lines, row, len(lines[row])); ok {
+		return nil
+	}
+	return append(candidate[:row], candidate[row+1:]...)
+}
+
+// removeTokens drops size tokens of a line, decrementing the count of its length.
+func removeTokens(lines [][]string, row, start, size int) [][]string {
+	if len(lines[row]) <= size {
+		return nil
+	}
+
+	candidate := cloneLines(lines)
+	adjustCount(candidate, row, len(lines[row]), size)
+	candidate[row] = append(candidate[row][:start], candidate[row][start+size:]...)
+	return candidate
+}
+
+func replaceToken(lines [][]string, row, col int, value string) [][]string {
+	candidate := cloneLines(lines)
+	candidate[row][col] = value
+	return candidate
+}
+
+// isCountToken reports whether a token looks like the length of a later line or
+// of the block of lines right after it.
+func isCountToken(lines [][]string, row, col int) bool {
+	n, err := strconv.Atoi(lines[row][col])
+	if err != nil || n <= 0 {
+		return false
+	}
+	for r := row + 1; r < len(lines); r++ {
+		if len(lines[r]) == n {
+			return true
+		}
+	}
+	return row+1 < len(lines) && blockEnd(lines, row+1)-(row+1) == n
+}
+
+// smallerNumbers lists candidate replacements for an integer token, closest to zero first.
+func smallerNumbers(token string) []string {
+	n, err := strconv.ParseInt(token, 10, 64)
+	if err != nil || n == 0 {
+		return nil
+	}
+
+	var candidates []string
+	seen := map[int64]bool{n: true}
+	for _, v := range []int64{0, sign(n), n / 2, n - sign(n)} {
+		if !seen[v] {
+			seen[v] = true
+			candidates = append(candidates, strconv.FormatInt(v, 10))
+		}
+	}
+	return candidates
+}
+
+func sign(n int64) int64 {
+	if n < 0 {
+		return -1
+	}
+	return 1
+}
+
+func init() {
+	minimizeCmd.Flags().BoolVarP(&minimizeQuiet, "quiet", "q", false, "Suppress build output")
+	minimizeCmd.Flags().StringVarP(&minimizeBrute, "brute", "b", defaultBrute, "Brute force solution (C++ source or executable)")
+	addLimitFlags(minimizeCmd)
+	addCheckerFlag(minimizeCmd)
+
+	rootCmd.AddCommand(minimizeCmd)
+}
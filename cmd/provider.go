@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/gocolly/colly/v2"
+
+	"github.com/ahmedYasserM/fo/internal/utils"
+)
+
+// Provider knows the problem pages of one judge.
+type Provider interface {
+	// Name is the name of the judge, used in messages.
+	Name() string
+	// Match reports whether u is a problem page of this judge.
+	Match(u *url.URL) bool
+	// IDs returns the contest and problem IDs of the problem page at u, if it has them.
+	IDs(u *url.URL) (contest, problem string)
+	// Resolve returns the problem page of a shorthand ID such as 799A, if this judge knows it.
+	Resolve(id string) (rawurl string, ok bool)
+	// Scrape extracts the samples and metadata from the problem page at u.
+	Scrape(doc *goquery.Document, u *url.URL) (*utils.Problem, []Testcase, error)
+}
+
+// providers are the supported judges, tried in order.
+var providers = []Provider{
+	codeforcesProvider{},
+	atcoderProvider{},
+	csesProvider{},
+	kattisProvider{},
+}
+
+// providerFor returns the judge whose problem page u is.
+func providerFor(u *url.URL) (Provider, error) {
+	for _, provider := range providers {
+		if provider.Match(u) {
+			return provider, nil
+		}
+	}
+	names := make([]string, len(providers))
+	for i, provider := range providers {
+		names[i] = provider.Name()
+	}
+	return nil, fmt.Errorf("%s is not a problem page of a supported judge (%s)", u, strings.Join(names, ", "))
+}
+
+// resolveProblem returns the problem page a URL or a shorthand ID stands for.
+func resolveProblem(arg string) (string, error) {
+	if u, err := url.Parse(arg); err == nil && u.Scheme != "" && u.Host != "" {
+		return arg, nil
+	}
+	// URLs copied without their scheme, e.g. codeforces.com/contest/799/problem/A
+	if u, err := url.Parse("https://" + arg); err == nil {
+		if _, err := providerFor(u); err == nil {
+			return u.String(), nil
+		}
+	}
+	for _, provider := range providers {
+		if rawurl, ok := provider.Resolve(strings.TrimSpace(arg)); ok {
+			return rawurl, nil
+		}
+	}
+	return "", fmt.Errorf("unknown problem %q (expected a URL or an ID like 799A, 799/A, gym:102001K, abc300_d, cses:1068 or kattis:hello)", arg)
+}
+
+// hostIs reports whether u is on host or one of its subdomains.
+func hostIs(u *url.URL, host string) bool {
+	h := strings.ToLower(u.Hostname())
+	return h == host || strings.HasSuffix(h, "."+host)
+}
+
+// scrapeProblem reads the samples and metadata from a problem page of any supported judge.
+func scrapeProblem(rawurl string) (*utils.Problem, []Testcase, error) {
+	u, err := url.Parse(rawurl)
+	if err != nil || u.Host == "" {
+		return nil, nil, fmt.Errorf("invalid problem URL %q", rawurl)
+	}
+	provider, err := providerFor(u)
+	if err != nil {
+		return nil, nil, err
+	}
+	doc, err := fetchPage(rawurl)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	problem, tests, err := provider.Scrape(doc, u)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", provider.Name(), err)
+	}
+	if len(tests) == 0 {
+		return nil, nil, fmt.Errorf("%s: could not find matching sample inputs and outputs", provider.Name())
+	}
+	problem.URL = rawurl
+	problem.ContestID, problem.ProblemID = provider.IDs(u)
+	return problem, tests, nil
+}
+
+// fetchPage downloads and parses an HTML page.
+func fetchPage(rawurl string) (*goquery.Document, error) {
+	c := newCollector()
+
+	var doc *goquery.Document
+	var parseErr error
+	c.OnResponse(func(r *colly.Response) {
+		doc, parseErr = goquery.NewDocumentFromReader(bytes.NewReader(r.Body))
+	})
+
+	if err := c.Visit(rawurl); err != nil {
+		return nil, fmt.Errorf("failed to visit URL %w", err)
+	}
+	if parseErr != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", rawurl, parseErr)
+	}
+	return doc, nil
+}
+
+// pairSamples matches sample inputs with outputs, failing when their counts differ.
+func pairSamples(inputs, outputs []string) ([]Testcase, error) {
+	if len(inputs) != len(outputs) {
+		return nil, fmt.Errorf("found %d sample input(s) but %d output(s)", len(inputs), len(outputs))
+	}
+	tests := make([]Testcase, len(inputs))
+	for i := range inputs {
+		tests[i] = Testcase{Input: inputs[i], Expected: outputs[i]}
+	}
+	return tests, nil
+}
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+
+	"github.com/ahmedYasserM/fo/internal/utils"
+)
+
+// atcoderProvider handles AtCoder tasks, e.g. https://atcoder.jp/contests/abc300/tasks/abc300_d.
+type atcoderProvider struct{}
+
+var (
+	atcoderPathRegex = regexp.MustCompile(`^/contests/([\w-]+)/tasks/([\w-]+)`)
+	// Task titles start with their index, e.g. "D - AABCC"
+	atcoderTitleRegex = regexp.MustCompile(`^[A-Za-z][0-9]*\s+-\s+`)
+	// Task IDs like abc300_d, the contest followed by the problem
+	atcoderIDRegex = regexp.MustCompile(`^([a-z][a-z0-9_-]*)_([a-z][0-9]?|[0-9]+)$`)
+)
+
+func (atcoderProvider) Name() string { return "AtCoder" }
+
+func (atcoderProvider) Match(u *url.URL) bool {
+	return hostIs(u, "atcoder.jp") && atcoderPathRegex.MatchString(u.Path)
+}
+
+func (atcoderProvider) IDs(u *url.URL) (contest, problem string) {
+	matches := atcoderPathRegex.FindStringSubmatch(u.Path)
+	if matches == nil {
+		return "", ""
+	}
+	// Task abc300_d is problem D of abc300
+	task := matches[2]
+	return matches[1], strings.ToUpper(task[strings.LastIndex(task, "_")+1:])
+}
+
+func (atcoderProvider) Resolve(id string) (string, bool) {
+	matches := atcoderIDRegex.FindStringSubmatch(id)
+	if matches == nil {
+		return "", false
+	}
+	return "https://atcoder.jp/contests/" + matches[1] + "/tasks/" + id, true
+}
+
+func (atcoderProvider) Scrape(doc *goquery.Document, u *url.URL) (*utils.Problem, []Testcase, error) {
+	problem := &utils.Problem{}
+
+	heading := doc.Find("span.h2").First()
+	problem.Title = atcoderTitleRegex.ReplaceAllString(strings.TrimSpace(heading.Contents().First().Text()), "")
+	// "Time Limit: 2 sec / Memory Limit: 1024 MB", in Japanese on Japanese pages
+	limits := heading.NextAllFiltered("p").First().Text()
+	if timeLimit, memory, found := strings.Cut(limits, "/"); found {
+		if limit, ok := parseTimeLimit(timeLimit); ok {
+			problem.TimeLimit = limit
+		}
+		if limit, ok := parseMemoryLimit(memory); ok {
+			problem.MemoryLimit = limit
+		}
+	}
+
+	// Statements come in Japanese and English; read the samples of one of them
+	statement := doc.Find("#task-statement span.lang-en")
+	if statement.Length() == 0 {
+		statement = doc.Find("#task-statement")
+	}
+	var inputs, outputs []string
+	statement.Find("h3").Each(func(_ int, h3 *goquery.Selection) {
+		title := strings.TrimSpace(h3.Text())
+		pre := h3.NextAllFiltered("pre").First()
+		switch {
+		case strings.HasPrefix(title, "Sample Input"), strings.HasPrefix(title, "入力例"):
+			inputs = append(inputs, strings.TrimSpace(extractPreText(pre)))
+		case strings.HasPrefix(title, "Sample Output"), strings.HasPrefix(title, "出力例"):
+			outputs = append(outputs, strings.TrimSpace(extractPreText(pre)))
+		}
+	})
+	tests, err := pairSamples(inputs, outputs)
+	return problem, tests, err
+}
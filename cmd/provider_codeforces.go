@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+
+	"github.com/ahmedYasserM/fo/internal/utils"
+)
+
+// codeforcesProvider handles contest, problemset and gym problems of Codeforces.
+type codeforcesProvider struct{}
+
+var (
+	// Problem titles start with their index, e.g. "C1. Pokémon Army (easy version)"
+	titleIndexRegex = regexp.MustCompile(`^[A-Z][0-9]?\.\s+`)
+	// Contest and problem IDs of /contest/799/problem/A, /problemset/problem/799/A and /gym/102001/problem/K
+	problemPathRegex = regexp.MustCompile(`/(?:contest|gym)/(\d+)/problem/(\w+)|/problemset/problem/(\d+)/(\w+)`)
+	// Shorthand IDs like 799A, 799/A, cf:799A and gym:102001K
+	codeforcesIDRegex = regexp.MustCompile(`^(?i)(cf:|gym:)?(\d+)/?([A-Z][0-9]?)$`)
+)
+
+// gymContestStart is the first contest ID of the gym; lower ones are regular contests.
+const gymContestStart = 100000
+
+func (codeforcesProvider) Name() string { return "Codeforces" }
+
+func (codeforcesProvider) Match(u *url.URL) bool {
+	return hostIs(u, "codeforces.com") && problemPathRegex.MatchString(u.Path)
+}
+
+func (codeforcesProvider) IDs(u *url.URL) (contest, problem string) {
+	matches := problemPathRegex.FindStringSubmatch(u.Path)
+	switch {
+	case matches == nil:
+		return "", ""
+	case matches[1] != "":
+		return matches[1], matches[2]
+	default:
+		return matches[3], matches[4]
+	}
+}
+
+func (codeforcesProvider) Resolve(id string) (string, bool) {
+	matches := codeforcesIDRegex.FindStringSubmatch(id)
+	if matches == nil {
+		return "", false
+	}
+	kind := "contest"
+	if contest, _ := strconv.Atoi(matches[2]); strings.EqualFold(matches[1], "gym:") || contest >= gymContestStart {
+		kind = "gym"
+	}
+	return fmt.Sprintf("https://codeforces.com/%s/%s/problem/%s", kind, matches[2], strings.ToUpper(matches[3])), true
+}
+
+func (codeforcesProvider) Scrape(doc *goquery.Document, u *url.URL) (*utils.Problem, []Testcase, error) {
+	problem := &utils.Problem{}
+
+	header := doc.Find("div.problem-statement div.header")
+	problem.Title = titleIndexRegex.ReplaceAllString(strings.TrimSpace(header.Find("div.title").First().Text()), "")
+	if limit, ok := parseTimeLimit(propertyValue(header.Find("div.time-limit"))); ok {
+		problem.TimeLimit = limit
+	}
+	if limit, ok := parseMemoryLimit(propertyValue(header.Find("div.memory-limit"))); ok {
+		problem.MemoryLimit = limit
+	}
+	problem.InputFile = parseIOFile(propertyValue(header.Find("div.input-file")))
+	problem.OutputFile = parseIOFile(propertyValue(header.Find("div.output-file")))
+
+	var inputs, outputs []string
+	doc.Find("div.sample-test div.input pre").Each(func(_ int, pre *goquery.Selection) {
+		inputs = append(inputs, strings.TrimSpace(extractPreText(pre)))
+	})
+	doc.Find("div.sample-test div.output pre").Each(func(_ int, pre *goquery.Selection) {
+		outputs = append(outputs, strings.TrimSpace(extractPreText(pre)))
+	})
+	tests, err := pairSamples(inputs, outputs)
+	return problem, tests, err
+}
+
+// propertyValue returns the text of a statement header entry without its title,
+// e.g. "2 seconds" for the "time limit per test" entry.
+func propertyValue(sel *goquery.Selection) string {
+	sel = sel.First().Clone()
+	sel.Find("div.property-title").Remove()
+	return strings.TrimSpace(sel.Text())
+}
+
+// parseIOFile returns the file a statement header like "input.txt" names,
+// or "" for "standard input" and "standard output".
+func parseIOFile(text string) string {
+	if strings.HasPrefix(text, "standard ") {
+		return ""
+	}
+	return text
+}
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+
+	"github.com/ahmedYasserM/fo/internal/utils"
+)
+
+// csesProvider handles the CSES problem set, e.g. https://cses.fi/problemset/task/1068.
+type csesProvider struct{}
+
+var (
+	csesPathRegex = regexp.MustCompile(`^/problemset/(?:task|view)/(\d+)`)
+	csesIDRegex   = regexp.MustCompile(`^(?i)cses:(\d+)$`)
+)
+
+func (csesProvider) Name() string { return "CSES" }
+
+func (csesProvider) Match(u *url.URL) bool {
+	return hostIs(u, "cses.fi") && csesPathRegex.MatchString(u.Path)
+}
+
+func (csesProvider) IDs(u *url.URL) (contest, problem string) {
+	if matches := csesPathRegex.FindStringSubmatch(u.Path); matches != nil {
+		return "", matches[1]
+	}
+	return "", ""
+}
+
+func (csesProvider) Resolve(id string) (string, bool) {
+	matches := csesIDRegex.FindStringSubmatch(id)
+	if matches == nil {
+		return "", false
+	}
+	return "https://cses.fi/problemset/task/" + matches[1], true
+}
+
+func (csesProvider) Scrape(doc *goquery.Document, u *url.URL) (*utils.Problem, []Testcase, error) {
+	problem := &utils.Problem{}
+	problem.Title = strings.TrimSpace(doc.Find("div.title-block h1").First().Text())
+
+	// <li><b>Time limit:</b> 1.00 s</li><li><b>Memory limit:</b> 512 MB</li>
+	doc.Find("ul.task-constraints li").Each(func(_ int, li *goquery.Selection) {
+		text := li.Text()
+		switch {
+		case strings.Contains(text, "Time limit"):
+			if limit, ok := parseTimeLimit(text); ok {
+				problem.TimeLimit = limit
+			}
+		case strings.Contains(text, "Memory limit"):
+			if limit, ok := parseMemoryLimit(text); ok {
+				problem.MemoryLimit = limit
+			}
+		}
+	})
+
+	// Each example is an "Input:" paragraph followed by a <pre>, then the same for "Output:"
+	var inputs, outputs []string
+	doc.Find("div.content pre").Each(func(_ int, pre *goquery.Selection) {
+		switch strings.TrimSpace(pre.PrevAllFiltered("p").First().Text()) {
+		case "Input:":
+			inputs = append(inputs, strings.TrimSpace(extractPreText(pre)))
+		case "Output:":
+			outputs = append(outputs, strings.TrimSpace(extractPreText(pre)))
+		}
+	})
+	tests, err := pairSamples(inputs, outputs)
+	return problem, tests, err
+}
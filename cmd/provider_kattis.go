@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+
+	"github.com/ahmedYasserM/fo/internal/utils"
+)
+
+// kattisProvider handles Kattis problems, e.g. https://open.kattis.com/problems/hello,
+// also within contests on any Kattis subdomain.
+type kattisProvider struct{}
+
+var (
+	kattisPathRegex = regexp.MustCompile(`^(?:/contests/([\w-]+))?/problems/([\w-]+)`)
+	kattisIDRegex   = regexp.MustCompile(`^(?i)kattis:([\w-]+)$`)
+)
+
+func (kattisProvider) Name() string { return "Kattis" }
+
+func (kattisProvider) Match(u *url.URL) bool {
+	return hostIs(u, "kattis.com") && kattisPathRegex.MatchString(u.Path)
+}
+
+func (kattisProvider) IDs(u *url.URL) (contest, problem string) {
+	if matches := kattisPathRegex.FindStringSubmatch(u.Path); matches != nil {
+		return matches[1], matches[2]
+	}
+	return "", ""
+}
+
+func (kattisProvider) Resolve(id string) (string, bool) {
+	matches := kattisIDRegex.FindStringSubmatch(id)
+	if matches == nil {
+		return "", false
+	}
+	return "https://open.kattis.com/problems/" + matches[1], true
+}
+
+func (kattisProvider) Scrape(doc *goquery.Document, u *url.URL) (*utils.Problem, []Testcase, error) {
+	problem := &utils.Problem{}
+	problem.Title = strings.TrimSpace(doc.Find("h1").First().Text())
+
+	// The sidebar lists "CPU Time limit" and "Memory limit" headings followed by their values
+	doc.Find("span, dt, th").Each(func(_ int, heading *goquery.Selection) {
+		value := heading.Next().Text()
+		switch strings.TrimSpace(heading.Text()) {
+		case "CPU Time limit":
+			if limit, ok := parseTimeLimit(value); ok {
+				problem.TimeLimit = limit
+			}
+		case "Memory limit":
+			if limit, ok := parseMemoryLimit(value); ok {
+				problem.MemoryLimit = limit
+			}
+		}
+	})
+
+	// Each sample is a table with the input and the output side by side;
+	// those of interactive problems hold a dialogue instead and are skipped
+	var inputs, outputs []string
+	doc.Find("table.sample").Each(func(_ int, table *goquery.Selection) {
+		pres := table.Find("td pre")
+		if pres.Length() != 2 {
+			return
+		}
+		inputs = append(inputs, strings.TrimSpace(extractPreText(pres.Eq(0))))
+		outputs = append(outputs, strings.TrimSpace(extractPreText(pres.Eq(1))))
+	})
+	tests, err := pairSamples(inputs, outputs)
+	return problem, tests, err
+}
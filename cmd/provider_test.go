@@ -0,0 +1,210 @@
+package cmd
+
+import (
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// The fixtures in testdata/providers are problem pages of each judge, trimmed
+// to the statement and the parts of the layout around it.
+func TestProviderScrape(t *testing.T) {
+	tests := []struct {
+		fixture     string
+		url         string
+		provider    string
+		title       string
+		timeLimit   time.Duration
+		memoryLimit int
+		contestID   string
+		problemID   string
+		inputFile   string
+		outputFile  string
+		samples     []Testcase
+	}{
+		{
+			fixture:     "codeforces_contest.html",
+			url:         "https://codeforces.com/contest/799/problem/A",
+			provider:    "Codeforces",
+			title:       "Carrot Cakes",
+			timeLimit:   time.Second,
+			memoryLimit: 256,
+			contestID:   "799",
+			problemID:   "A",
+			samples: []Testcase{
+				{Input: "8 6 4 5", Expected: "YES"},
+				{Input: "8 6 4 6", Expected: "NO"},
+				{Input: "10 3 11 4", Expected: "NO"},
+				{Input: "4 2 1 4", Expected: "YES"},
+			},
+		},
+		{
+			fixture:     "codeforces_problemset.html",
+			url:         "https://codeforces.com/problemset/problem/1360/C",
+			provider:    "Codeforces",
+			title:       "Similar Pairs",
+			timeLimit:   2 * time.Second,
+			memoryLimit: 256,
+			contestID:   "1360",
+			problemID:   "C",
+			samples: []Testcase{
+				{Input: "3\n4\n11 14 16 12\n2\n1 8\n4\n1 1 1 1", Expected: "YES\nNO\nYES"},
+			},
+		},
+		{
+			fixture:     "codeforces_gym.html",
+			url:         "https://codeforces.com/gym/102001/problem/K",
+			provider:    "Codeforces",
+			title:       "Knight Tour",
+			timeLimit:   2500 * time.Millisecond,
+			memoryLimit: 512,
+			contestID:   "102001",
+			problemID:   "K",
+			inputFile:   "knight.in",
+			outputFile:  "knight.out",
+			samples: []Testcase{
+				{Input: "8 1\n1 1", Expected: "2"},
+				{Input: "3 2\n2 2", Expected: "0"},
+			},
+		},
+		{
+			fixture:     "atcoder_en.html",
+			url:         "https://atcoder.jp/contests/abc300/tasks/abc300_d?lang=en",
+			provider:    "AtCoder",
+			title:       "AABCC",
+			timeLimit:   2 * time.Second,
+			memoryLimit: 1024,
+			contestID:   "abc300",
+			problemID:   "D",
+			samples: []Testcase{
+				{Input: "1000", Expected: "3"},
+				{Input: "1000000000000", Expected: "2817785"},
+			},
+		},
+		{
+			fixture:     "atcoder_ja.html",
+			url:         "https://atcoder.jp/contests/abc001/tasks/abc001_1",
+			provider:    "AtCoder",
+			title:       "積雪深差",
+			timeLimit:   2 * time.Second,
+			memoryLimit: 64,
+			contestID:   "abc001",
+			problemID:   "1",
+			samples: []Testcase{
+				{Input: "15\n10", Expected: "5"},
+				{Input: "0\n0", Expected: "0"},
+				{Input: "5\n20", Expected: "-15"},
+			},
+		},
+		{
+			fixture:     "cses.html",
+			url:         "https://cses.fi/problemset/task/1068",
+			provider:    "CSES",
+			title:       "Weird Algorithm",
+			timeLimit:   time.Second,
+			memoryLimit: 512,
+			problemID:   "1068",
+			samples: []Testcase{
+				{Input: "3", Expected: "3 10 5 16 8 4 2 1"},
+			},
+		},
+		{
+			fixture:     "kattis.html",
+			url:         "https://open.kattis.com/problems/twostones",
+			provider:    "Kattis",
+			title:       "Two Stones",
+			timeLimit:   time.Second,
+			memoryLimit: 1024,
+			problemID:   "twostones",
+			samples: []Testcase{
+				{Input: "1", Expected: "Alice"},
+				{Input: "2", Expected: "Bob"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			page, err := os.Open(filepath.Join("testdata", "providers", tt.fixture))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer page.Close()
+			doc, err := goquery.NewDocumentFromReader(page)
+			if err != nil {
+				t.Fatal(err)
+			}
+			u, err := url.Parse(tt.url)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			provider, err := providerFor(u)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if provider.Name() != tt.provider {
+				t.Errorf("provider = %s, want %s", provider.Name(), tt.provider)
+			}
+			problem, samples, err := provider.Scrape(doc, u)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if problem.Title != tt.title {
+				t.Errorf("title = %q, want %q", problem.Title, tt.title)
+			}
+			if problem.TimeLimit != tt.timeLimit {
+				t.Errorf("time limit = %s, want %s", problem.TimeLimit, tt.timeLimit)
+			}
+			if problem.MemoryLimit != tt.memoryLimit {
+				t.Errorf("memory limit = %d, want %d", problem.MemoryLimit, tt.memoryLimit)
+			}
+			if problem.InputFile != tt.inputFile || problem.OutputFile != tt.outputFile {
+				t.Errorf("I/O files = %q, %q, want %q, %q", problem.InputFile, problem.OutputFile, tt.inputFile, tt.outputFile)
+			}
+			if contest, problem := provider.IDs(u); contest != tt.contestID || problem != tt.problemID {
+				t.Errorf("IDs = %q, %q, want %q, %q", contest, problem, tt.contestID, tt.problemID)
+			}
+			if !slices.Equal(samples, tt.samples) {
+				t.Errorf("samples = %q, want %q", samples, tt.samples)
+			}
+		})
+	}
+}
+
+func TestResolveProblem(t *testing.T) {
+	tests := map[string]string{
+		"799A":         "https://codeforces.com/contest/799/problem/A",
+		"799/A":        "https://codeforces.com/contest/799/problem/A",
+		"cf:1360c":     "https://codeforces.com/contest/1360/problem/C",
+		"1383B1":       "https://codeforces.com/contest/1383/problem/B1",
+		"gym:102001K":  "https://codeforces.com/gym/102001/problem/K",
+		"102001/K":     "https://codeforces.com/gym/102001/problem/K",
+		"abc300_d":     "https://atcoder.jp/contests/abc300/tasks/abc300_d",
+		"abc001_1":     "https://atcoder.jp/contests/abc001/tasks/abc001_1",
+		"cses:1068":    "https://cses.fi/problemset/task/1068",
+		"kattis:hello": "https://open.kattis.com/problems/hello",
+		"https://codeforces.com/contest/799/problem/A": "https://codeforces.com/contest/799/problem/A",
+		"codeforces.com/contest/799/problem/A":         "https://codeforces.com/contest/799/problem/A",
+		"atcoder.jp/contests/abc300/tasks/abc300_d":    "https://atcoder.jp/contests/abc300/tasks/abc300_d",
+	}
+	for id, want := range tests {
+		got, err := resolveProblem(id)
+		if err != nil {
+			t.Errorf("resolveProblem(%q) failed: %v", id, err)
+		} else if got != want {
+			t.Errorf("resolveProblem(%q) = %s, want %s", id, got, want)
+		}
+	}
+
+	for _, id := range []string{"", "A", "799", "hello", "cses:abc", "example.com/problem/1"} {
+		if got, err := resolveProblem(id); err == nil {
+			t.Errorf("resolveProblem(%q) = %s, want an error", id, got)
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package cmd
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// reportEntry is the machine-readable outcome of a single test case.
+type reportEntry struct {
+	Index    int     `json:"index"`
+	Label    string  `json:"label,omitempty"`
+	Verdict  Verdict `json:"verdict,omitempty"`
+	TimeMs   float64 `json:"time_ms"`
+	CPUMs    float64 `json:"cpu_ms"`
+	MemoryKB int64   `json:"memory_kb"`
+	Input    string  `json:"input"`
+	Expected string  `json:"expected"`
+	Actual   string  `json:"actual"`
+	Message  string  `json:"message,omitempty"`
+	Error    string  `json:"error,omitempty"` // set when the program could not be run at all
+	Stderr   string  `json:"stderr,omitempty"`
+}
+
+// newReportEntry converts a test result into a report entry.
+func newReportEntry(index int, test Testcase, result testResult) reportEntry {
+	entry := reportEntry{
+		Index:    index,
+		Label:    test.Label,
+		Verdict:  result.Verdict,
+		Input:    test.Input,
+		Expected: test.Expected,
+		Message:  result.Message,
+	}
+	if result.Err != nil {
+		entry.Verdict = ""
+		entry.Error = result.Err.Error()
+	}
+	if res := result.Run; res != nil {
+		entry.TimeMs = float64(res.Wall.Microseconds()) / 1000
+		entry.CPUMs = float64(res.CPU.Microseconds()) / 1000
+		entry.MemoryKB = res.Memory / 1024
+		entry.Actual = res.Stdout
+		entry.Stderr = res.Stderr
+		if entry.Message == "" && entry.Verdict == VerdictRE {
+			entry.Message = runtimeErrorReason(res)
+		}
+	}
+	return entry
+}
+
+func (e reportEntry) name() string {
+	return Testcase{TestMeta: TestMeta{Label: e.Label}}.name(e.Index)
+}
+
+// reportFormats lists the accepted values of --format.
+var reportFormats = []string{"human", "json", "junit", "tap"}
+
+// writeReport writes the test results in a machine-readable format.
+func writeReport(w io.Writer, format string, entries []reportEntry) error {
+	switch format {
+	case "json":
+		return writeJSONReport(w, entries)
+	case "junit":
+		return writeJUnitReport(w, entries)
+	case "tap":
+		return writeTAPReport(w, entries)
+	}
+	return fmt.Errorf("unknown format %q (expected %s)", format, strings.Join(reportFormats, ", "))
+}
+
+func countPassed(entries []reportEntry) int {
+	passed := 0
+	for _, e := range entries {
+		if e.Verdict.Passed() {
+			passed++
+		}
+	}
+	return passed
+}
+
+func writeJSONReport(w io.Writer, entries []reportEntry) error {
+	if entries == nil {
+		entries = []reportEntry{} // "tests": [] rather than null when nothing ran
+	}
+	report := struct {
+		Passed int           `json:"passed"`
+		Total  int           `json:"total"`
+		Tests  []reportEntry `json:"tests"`
+	}{countPassed(entries), len(entries), entries}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(report)
+}
+
+type junitFailure struct {
+	Type    string `xml:"type,attr"`
+	Message string `xml:"message,attr"`
+	Body    string `xml:",chardata"`
+}
+
+type junitTestcase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Error     *junitFailure `xml:"error,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitTestsuite struct {
+	XMLName   xml.Name        `xml:"testsuite"`
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Errors    int             `xml:"errors,attr"`
+	Time      string          `xml:"time,attr"`
+	Testcases []junitTestcase `xml:"testcase"`
+}
+
+func writeJUnitReport(w io.Writer, entries []reportEntry) error {
+	suite := junitTestsuite{Name: "fo", Tests: len(entries)}
+	var total float64
+	for _, e := range entries {
+		total += e.TimeMs
+		tc := junitTestcase{
+			Name:      e.name(),
+			Classname: "fo",
+			Time:      fmt.Sprintf("%.3f", e.TimeMs/1000),
+			SystemOut: e.Actual,
+		}
+		switch {
+		case e.Error != "":
+			suite.Errors++
+			tc.Error = &junitFailure{Type: "ERROR", Message: e.Error}
+		case !e.Verdict.Passed():
+			suite.Failures++
+			tc.Failure = &junitFailure{
+				Type:    string(e.Verdict),
+				Message: strings.TrimSpace(string(e.Verdict) + " " + e.Message),
+				Body:    "Expected:\n" + e.Expected,
+			}
+		}
+		suite.Testcases = append(suite.Testcases, tc)
+	}
+	suite.Time = fmt.Sprintf("%.3f", total/1000)
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(suite); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func writeTAPReport(w io.Writer, entries []reportEntry) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "TAP version 13\n1..%d\n", len(entries))
+	// TAP numbers results 1..N, so the index of a selected test only goes in its description
+	for i, e := range entries {
+		if e.Verdict.Passed() {
+			fmt.Fprintf(&b, "ok %d - %s # time=%.3fms\n", i+1, e.name(), e.TimeMs)
+			continue
+		}
+
+		fmt.Fprintf(&b, "not ok %d - %s\n  ---\n", i+1, e.name())
+		if e.Error != "" {
+			fmt.Fprintf(&b, "  error: %q\n", e.Error)
+		} else {
+			fmt.Fprintf(&b, "  verdict: %s\n  message: %q\n", e.Verdict, e.Message)
+			fmt.Fprintf(&b, "  time_ms: %.3f\n  memory_kb: %d\n", e.TimeMs, e.MemoryKB)
+			fmt.Fprintf(&b, "  expected: %q\n  actual: %q\n", e.Expected, e.Actual)
+		}
+		b.WriteString("  ...\n")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
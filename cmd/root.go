@@ -14,7 +14,7 @@ var rootCmd = &cobra.Command{
 	Short: "fo is a CLI tool for competitive programming workflows",
 	Long: `fo is a powerful command-line interface tool designed to streamline
 your competitive programming workflow. It handles fetching sample cases from
-Codeforces, compiling C++ code, running tests, and managing boilerplate.`,
+Codeforces, AtCoder, CSES and Kattis, compiling C++ code, running tests, and managing boilerplate.`,
 }
 
 func Execute() {
@@ -1,8 +1,9 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
-	"os"
+	"strings"
 
 	"github.com/ahmedYasserM/fo/internal/colors"
 	"github.com/ahmedYasserM/fo/internal/utils"
@@ -10,7 +11,10 @@ import (
 	"github.com/spf13/cobra"
 )
 
-var runQuiet bool
+var (
+	runQuiet bool
+	runWatch bool
+)
 
 var runCmd = &cobra.Command{
 	Use:   "run",
@@ -19,55 +23,50 @@ var runCmd = &cobra.Command{
 		if err := utils.LoadConfigOnce(runQuiet); err != nil {
 			return err
 		}
-
-		if !utils.PathExists(utils.CmdConfig.SourceName) {
-			return fmt.Errorf("%sError: %s%s not found. Cannot compile or run.%s", colors.RED, colors.BOLD, utils.CmdConfig.SourceName, colors.RESET)
+		if err := applyProfile(cmd); err != nil {
+			return err
 		}
-
-		needsBuild := false
-		mainCppInfo, err := os.Stat(utils.CmdConfig.SourceName)
-		if err != nil {
-			return fmt.Errorf("could not get info for %s: %w", utils.CmdConfig.SourceName, err)
+		if err := applyLanguage(cmd); err != nil {
+			return err
 		}
 
-		mainExecInfo, err := os.Stat(utils.CmdConfig.ExecutableName)
-		if os.IsNotExist(err) {
-			needsBuild = true
-		} else if err != nil {
-			return fmt.Errorf("could not get info for %s: %w", utils.CmdConfig.ExecutableName, err)
-		} else {
-			if mainCppInfo.ModTime().After(mainExecInfo.ModTime()) {
-				needsBuild = true
-			}
+		if runWatch {
+			return watchLoop(sourceFiles, runProgram)
 		}
+		return runProgram(context.Background())
+	},
+}
 
-		if needsBuild {
-			if !runQuiet {
-				fmt.Printf("%sExecutable '%s' is missing or outdated. Building...%s\n", colors.YELLOW, utils.CmdConfig.ExecutableName, colors.RESET)
-			}
-			if err := utils.BuildExecutable(runQuiet); err != nil {
-				return fmt.Errorf("%sBuild failed, cannot run:%s %w", colors.RED, colors.RESET, err)
-			}
-			if !runQuiet {
-				fmt.Printf("%s✅ Build successful! Executable: %s%s\n", colors.GREEN, utils.CmdConfig.ExecutableName, colors.RESET)
-			}
-		} else {
-			if !runQuiet {
-				fmt.Printf("%s'%s' is up to date, skipping build.%s\n", colors.CYAN, utils.CmdConfig.ExecutableName, colors.RESET)
-			}
-		}
+// runProgram builds the executable if needed and runs it on the terminal until ctx is done.
+func runProgram(ctx context.Context) error {
+	if !utils.PathExists(utils.CmdConfig.SourceName) {
+		return fmt.Errorf("%sError: %s%s not found. Cannot compile or run.%s", colors.RED, colors.BOLD, utils.CmdConfig.SourceName, colors.RESET)
+	}
 
-		fmt.Printf("%sRunning '%s'...%s\n", colors.CYAN, utils.CmdConfig.ExecutableName, colors.RESET)
-		err = utils.ExecuteCmd(fmt.Sprintf("./%s", utils.CmdConfig.ExecutableName))
-		if err != nil {
-			return fmt.Errorf("%sProgram exited with error:%s %w", colors.RED, colors.RESET, err)
-		}
+	if err := ensureBuilt(runQuiet); err != nil {
+		return fmt.Errorf("%sBuild failed, cannot run:%s %w", colors.RED, colors.RESET, err)
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
 
-		return nil
-	},
+	command, err := utils.RunCommand()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%sRunning '%s'...%s\n", colors.CYAN, strings.Join(command, " "), colors.RESET)
+	err = utils.ExecuteCmdContext(ctx, utils.SanitizerEnv(), command[0], command[1:]...)
+	if err != nil {
+		return fmt.Errorf("%sProgram exited with error:%s %w", colors.RED, colors.RESET, err)
+	}
+
+	return nil
 }
 
 func init() {
 	runCmd.Flags().BoolVarP(&runQuiet, "quiet", "q", false, "Suppress build output")
+	runCmd.Flags().BoolVarP(&runWatch, "watch", "w", false, "Rebuild and rerun the program whenever the source or its headers change")
+	addProfileFlag(runCmd)
+	addLangFlag(runCmd)
 	rootCmd.AddCommand(runCmd)
 }
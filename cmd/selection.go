@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/ahmedYasserM/fo/internal/utils"
+	"gopkg.in/yaml.v3"
+)
+
+// stateFile remembers which tests failed in the last run of 'fo test', for --failed.
+const stateFile = ".fo-state.yaml"
+
+// testState is the content of stateFile.
+type testState struct {
+	Failed []int `yaml:"failed"` // 1-based test numbers
+}
+
+// parseSelectors turns selectors such as "3" and "5-7" into sorted, 1-based test numbers.
+func parseSelectors(args []string, count int) ([]int, error) {
+	var selected []int
+	for _, arg := range args {
+		first, last, isRange := strings.Cut(arg, "-")
+		if !isRange {
+			last = first
+		}
+
+		from, err := parseTestNumber(first, count)
+		if err != nil {
+			return nil, err
+		}
+		to, err := parseTestNumber(last, count)
+		if err != nil {
+			return nil, err
+		}
+		if from > to {
+			return nil, fmt.Errorf("invalid test range %q", arg)
+		}
+		for i := from; i <= to; i++ {
+			selected = append(selected, i)
+		}
+	}
+
+	slices.Sort(selected)
+	return slices.Compact(selected), nil
+}
+
+// loadFailedTests returns the tests that failed in the last run, ignoring numbers
+// beyond count left over from removed tests.
+func loadFailedTests(count int) ([]int, error) {
+	state, err := loadTestState()
+	if err != nil {
+		return nil, err
+	}
+	var failed []int
+	for _, index := range state.Failed {
+		if index >= 1 && index <= count {
+			failed = append(failed, index)
+		}
+	}
+	return failed, nil
+}
+
+// saveFailedTests records the failures of a run: the tests that ran replace their
+// previous outcome, while the others keep it.
+func saveFailedTests(ran, failed []int) error {
+	state, err := loadTestState()
+	if err != nil {
+		return err
+	}
+
+	kept := slices.DeleteFunc(state.Failed, func(index int) bool {
+		return slices.Contains(ran, index)
+	})
+	state.Failed = append(kept, failed...)
+	slices.Sort(state.Failed)
+
+	data, err := yaml.Marshal(state)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(stateFile, data, 0o644)
+}
+
+func loadTestState() (*testState, error) {
+	state := &testState{}
+	if !utils.PathExists(stateFile) {
+		return state, nil
+	}
+
+	data, err := utils.ReadFileToBytes(stateFile)
+	if err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", stateFile, err)
+	}
+	return state, nil
+}
+
+// allTests returns the test numbers 1 to count.
+func allTests(count int) []int {
+	selected := make([]int, count)
+	for i := range selected {
+		selected[i] = i + 1
+	}
+	return selected
+}
+
+// formatTestNumbers renders test numbers compactly, e.g. "1, 3-5".
+func formatTestNumbers(numbers []int) string {
+	var parts []string
+	for i := 0; i < len(numbers); {
+		j := i
+		for j+1 < len(numbers) && numbers[j+1] == numbers[j]+1 {
+			j++
+		}
+		if i == j {
+			parts = append(parts, strconv.Itoa(numbers[i]))
+		} else {
+			parts = append(parts, fmt.Sprintf("%d-%d", numbers[i], numbers[j]))
+		}
+		i = j + 1
+	}
+	return strings.Join(parts, ", ")
+}
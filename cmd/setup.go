@@ -10,14 +10,17 @@ import (
 	"github.com/spf13/cobra"
 )
 
-func preload() error {
+func preload(cmd *cobra.Command) error {
 
 	// Load config file
 	if err := utils.LoadConfigOnce(false); err != nil {
 		return fmt.Errorf("%s❌ %v%s\n", colors.RED, err, colors.RESET)
 	}
+	if err := applyLanguage(cmd); err != nil {
+		return fmt.Errorf("%s❌ %v%s\n", colors.RED, err, colors.RESET)
+	}
 
-	// Load C++ template
+	// Load the template of the solution's language
 	if err := utils.LoadTemplateOnce(); err != nil {
 		return fmt.Errorf("%s❌  %v%s\n", colors.RED, err, colors.RESET)
 	}
@@ -26,13 +29,14 @@ func preload() error {
 }
 
 var setupCmd = &cobra.Command{
-	Use:   "setup [URL]",
+	Use:   "setup <URL | ID>",
 	Short: "Sets up a new problem: fetches samples and creates source file if not exists",
 	Long: `This command streamlines the setup for a new competitive programming problem.
-It fetches sample test cases from the provided Codeforces URL using 'fo fetch'.
+It fetches sample test cases from the provided problem URL or ID (e.g. 799A) using 'fo fetch'.
 Then, if the source file does not already exist in the current directory, it creates it
-using the C++ template located in the configuration directory, with the filename
-determined by your configuration (default is 'main.cpp').
+using the template located in the configuration directory, with the filename
+determined by your configuration (default is 'main.cpp'). Use '--lang' to start
+a solution in another language, e.g. '--lang python' creates 'main.py'.
 
 You can customize the executable/file name in your config file, which affects which
 source file is created and used.
@@ -41,7 +45,7 @@ This helps keep your workflow flexible and consistent across projects.`,
 	Args: cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 
-		if err := preload(); err != nil {
+		if err := preload(cmd); err != nil {
 			fmt.Fprintln(os.Stderr, err)
 			os.Exit(1)
 		}
@@ -64,5 +68,6 @@ This helps keep your workflow flexible and consistent across projects.`,
 }
 
 func init() {
+	addLangFlag(setupCmd)
 	rootCmd.AddCommand(setupCmd)
 }
@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ahmedYasserM/fo/internal/colors"
+	"github.com/ahmedYasserM/fo/internal/utils"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	defaultBrute     = "brute.cpp"
+	defaultGenerator = "gen.cpp"
+)
+
+var (
+	stressQuiet      bool
+	stressBrute      string
+	stressGenerator  string
+	stressIterations int
+	stressSeed       int
+	stressMinimize   bool
+)
+
+var stressCmd = &cobra.Command{
+	Use:   "stress",
+	Short: "Stress tests the solution against a brute force solution on generated inputs",
+	Long: `Builds the solution, a brute force solution (default: brute.cpp) and a generator
+(default: gen.cpp), then repeatedly:
+  1. runs the generator with an incrementing seed as its only argument to get an input,
+  2. runs the brute force solution on it to get the expected output,
+  3. runs the solution on it and compares its output using the configured checker.
+
+It stops on the first mismatch and saves the failing input as a new test case.
+With --minimize, the failing input is shrunk first (see 'fo minimize').
+
+Example:
+  fo stress --iterations 500 --seed 1`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := utils.LoadConfigOnce(stressQuiet); err != nil {
+			return err
+		}
+
+		runner, brute, err := prepareBruteRun(cmd, stressBrute, stressQuiet)
+		if err != nil {
+			return err
+		}
+		generator, err := utils.PrepareProgram(stressGenerator, stressQuiet)
+		if err != nil {
+			return fmt.Errorf("failed to build generator: %w", err)
+		}
+
+		fmt.Printf("%sStress testing (time limit %s, memory limit %d MB)...%s\n", colors.CYAN, runner.opts.TimeLimit, runner.opts.MemoryLimit>>20, colors.RESET)
+		for i := 0; stressIterations == 0 || i < stressIterations; i++ {
+			seed := stressSeed + i
+
+			input, err := runHelper(generator, "", strconv.Itoa(seed))
+			if err != nil {
+				return fmt.Errorf("generator failed on seed %d: %w", seed, err)
+			}
+			expected, err := runHelper(brute, input)
+			if err != nil {
+				return fmt.Errorf("brute force solution failed on seed %d: %w", seed, err)
+			}
+
+			test := Testcase{Input: input, Expected: expected}
+			result := runner.run(context.Background(), test)
+			if result.Err != nil {
+				return fmt.Errorf("failed to run solution on seed %d: %w", seed, result.Err)
+			}
+			if result.Verdict.Passed() {
+				if !stressQuiet {
+					fmt.Printf("\r%sSeed %d: OK%s", colors.GREEN, seed, colors.RESET)
+				}
+				continue
+			}
+
+			fmt.Printf("\n%s❌ Found a failing input with seed %d.%s\n", colors.RED, seed, colors.RESET)
+			if stressMinimize {
+				return minimizeAndSave(runner, brute, input)
+			}
+			source, err := detectTestSource()
+			if err != nil {
+				return err
+			}
+			index, err := appendTestcase(source, test)
+			if err != nil {
+				return err
+			}
+			printTestResult(index, test, result, defaultDisplay())
+			fmt.Printf("%s✅ Saved the failing input as test #%d in %s%s\n", colors.GREEN, index, source.Name(), colors.RESET)
+			return nil
+		}
+
+		fmt.Printf("\n%s✅ No failing input found in %d iterations.%s\n", colors.GREEN, stressIterations, colors.RESET)
+		return nil
+	},
+}
+
+// runHelper runs a generator or brute force solution and returns its output.
+// Unlike the solution under test, helpers are expected to always succeed.
+func runHelper(path, input string, args ...string) (string, error) {
+	res, err := utils.RunWithLimits(context.Background(), utils.RunOptions{Input: input, TimeLimit: helperTimeLimit}, path, args...)
+	if err != nil {
+		return "", err
+	}
+	if res.TimedOut {
+		return "", fmt.Errorf("timed out after %s", helperTimeLimit)
+	}
+	if res.Failed() {
+		return "", fmt.Errorf("%s: %s", runtimeErrorReason(res), strings.TrimSpace(res.Stderr))
+	}
+	return res.Stdout, nil
+}
+
+func init() {
+	stressCmd.Flags().BoolVarP(&stressQuiet, "quiet", "q", false, "Suppress build output and progress")
+	stressCmd.Flags().StringVarP(&stressBrute, "brute", "b", defaultBrute, "Brute force solution (C++ source or executable)")
+	stressCmd.Flags().StringVarP(&stressGenerator, "gen", "g", defaultGenerator, "Input generator (C++ source or executable), called with the seed as argument")
+	stressCmd.Flags().IntVarP(&stressIterations, "iterations", "n", 1000, "Number of iterations; 0 runs until a failing input is found")
+	stressCmd.Flags().IntVarP(&stressSeed, "seed", "s", 1, "Seed of the first iteration")
+	stressCmd.Flags().BoolVar(&stressMinimize, "minimize", false, "Shrink the failing input before saving it")
+	addLimitFlags(stressCmd)
+	addCheckerFlag(stressCmd)
+
+	rootCmd.AddCommand(stressCmd)
+}
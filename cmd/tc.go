@@ -0,0 +1,334 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+
+	"github.com/ahmedYasserM/fo/internal/colors"
+	"github.com/ahmedYasserM/fo/internal/utils"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	tcInput  string
+	tcOutput string
+)
+
+// editorHeader is written above the test case opened in the editor.
+// The parser ignores everything before the first marker.
+const editorHeader = `# Write the input below the Input marker and the expected output below the Output marker.
+# Keep the marker lines; these comment lines are ignored. Save an empty input to cancel.
+
+`
+
+var tcCmd = &cobra.Command{
+	Use:   "tc",
+	Short: "Adds, edits, lists and removes test cases",
+	Long: `Manages the test cases of the current directory, in whichever layout they are
+stored (see 'fo test --help'), so they never have to be edited by hand.`,
+}
+
+var tcAddCmd = &cobra.Command{
+	Use:   "add",
+	Short: "Adds a test case",
+	Long: `Adds a test case after the existing ones. The input and expected output are read from:
+  --input/--output files, where '-' stands for stdin,
+  stdin, in the testcases.txt format ('--- Sample #N Input ---' / '--- Sample #N Output ---'),
+    which may hold several test cases,
+  otherwise $VISUAL or $EDITOR, opened on a template with both sections.
+
+Examples:
+  fo tc add
+  fo tc add --input in.txt --output out.txt
+  ./gen 42 | fo tc add --input - --output expected.txt`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := utils.LoadConfigOnce(true); err != nil {
+			return err
+		}
+		source, err := detectTestSource()
+		if err != nil {
+			return err
+		}
+
+		var tests []Testcase
+		switch {
+		case tcInput != "" || tcOutput != "":
+			test, err := readTestFiles(tcInput, tcOutput)
+			if err != nil {
+				return err
+			}
+			tests = []Testcase{test}
+		case !isTerminal(os.Stdin):
+			if tests, err = readTestcases(os.Stdin); err != nil {
+				return fmt.Errorf("error reading stdin: %w", err)
+			}
+			if len(tests) == 0 {
+				return fmt.Errorf("no test cases found on stdin (expected '--- Sample #N Input ---' and '--- Sample #N Output ---' markers)")
+			}
+		default:
+			test, err := editTestcase(Testcase{})
+			if err != nil {
+				return err
+			}
+			tests = []Testcase{test}
+		}
+
+		for _, test := range tests {
+			index, err := appendTestcase(source, test)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("%s✅ Added test #%d to %s%s\n", colors.GREEN, index, source.Name(), colors.RESET)
+		}
+		return nil
+	},
+}
+
+var tcEditCmd = &cobra.Command{
+	Use:   "edit N",
+	Short: "Edits a test case in $EDITOR",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		source, tests, err := loadTestsForTc()
+		if err != nil {
+			return err
+		}
+		index, err := parseTestNumber(args[0], len(tests))
+		if err != nil {
+			return err
+		}
+
+		test, err := editTestcase(tests[index-1])
+		if err != nil {
+			return err
+		}
+		tests[index-1] = test
+		if err := source.Save(tests); err != nil {
+			return err
+		}
+		fmt.Printf("%s✅ Updated test #%d in %s%s\n", colors.GREEN, index, source.Name(), colors.RESET)
+		return nil
+	},
+}
+
+var tcListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Lists the test cases with their sizes",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		source, tests, err := loadTestsForTc()
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("%s%d test case(s) in %s%s\n", colors.CYAN, len(tests), source.Name(), colors.RESET)
+		for i, test := range tests {
+			output := describeSize(test.Expected)
+			if test.answerless() {
+				output = "not checked"
+			}
+			fmt.Printf("%s#%-3d%s input %s   output %s", colors.BOLD, i+1, colors.RESET, describeSize(test.Input), output)
+			if test.Label != "" {
+				fmt.Printf("   %s%s%s", colors.CYAN, test.Label, colors.RESET)
+			}
+			fmt.Println()
+		}
+		return nil
+	},
+}
+
+var tcShowCmd = &cobra.Command{
+	Use:   "show N",
+	Short: "Shows the input and expected output of a test case",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		_, tests, err := loadTestsForTc()
+		if err != nil {
+			return err
+		}
+		index, err := parseTestNumber(args[0], len(tests))
+		if err != nil {
+			return err
+		}
+
+		test := tests[index-1]
+		fmt.Printf("%s=== %s ===%s\n", colors.BOLD, test.name(index), colors.RESET)
+		if meta := formatTestMeta(test.TestMeta); meta != "" {
+			fmt.Printf("%sMetadata:%s %s\n", colors.YELLOW, colors.RESET, meta)
+		}
+		fmt.Printf("%sInput:%s\n%s\n", colors.YELLOW, colors.RESET, strings.TrimRight(test.Input, "\n"))
+		if test.answerless() {
+			fmt.Printf("%sExpected:%s none, the output is not checked\n", colors.YELLOW, colors.RESET)
+		} else {
+			fmt.Printf("%sExpected:%s\n%s\n", colors.YELLOW, colors.RESET, strings.TrimRight(test.Expected, "\n"))
+		}
+		return nil
+	},
+}
+
+var tcRmCmd = &cobra.Command{
+	Use:   "rm N...",
+	Short: "Removes test cases",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		source, tests, err := loadTestsForTc()
+		if err != nil {
+			return err
+		}
+
+		var remove []int
+		for _, arg := range args {
+			index, err := parseTestNumber(arg, len(tests))
+			if err != nil {
+				return err
+			}
+			remove = append(remove, index)
+		}
+
+		// Delete from the back so the remaining numbers stay valid
+		slices.Sort(remove)
+		remove = slices.Compact(remove)
+		for _, index := range slices.Backward(remove) {
+			tests = slices.Delete(tests, index-1, index)
+		}
+		if err := source.Save(tests); err != nil {
+			return err
+		}
+		fmt.Printf("%s✅ Removed %d test case(s) from %s, %d left%s\n", colors.GREEN, len(remove), source.Name(), len(tests), colors.RESET)
+		return nil
+	},
+}
+
+var tcRenumberCmd = &cobra.Command{
+	Use:   "renumber",
+	Short: "Rewrites the test cases numbered 1, 2, 3, ...",
+	Long: `Rewrites the test cases in order, numbered from 1 without gaps, e.g. after
+editing testcases.txt by hand or deleting files from the tests directory.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		source, tests, err := loadTestsForTc()
+		if err != nil {
+			return err
+		}
+		if err := source.Save(tests); err != nil {
+			return err
+		}
+		fmt.Printf("%s✅ Renumbered %d test case(s) in %s%s\n", colors.GREEN, len(tests), source.Name(), colors.RESET)
+		return nil
+	},
+}
+
+func loadTestsForTc() (TestSource, []Testcase, error) {
+	if err := utils.LoadConfigOnce(true); err != nil {
+		return nil, nil, err
+	}
+	return loadTests()
+}
+
+// readTestFiles reads a test case from an input and an expected output file, '-' being stdin.
+func readTestFiles(input, output string) (Testcase, error) {
+	if input == "" {
+		return Testcase{}, fmt.Errorf("--input is required with --output")
+	}
+	if input == "-" && output == "-" {
+		return Testcase{}, fmt.Errorf("only one of --input and --output can be read from stdin")
+	}
+
+	read := func(path string) (string, error) {
+		if path == "" {
+			return "", nil
+		}
+		if path == "-" {
+			data, err := io.ReadAll(os.Stdin)
+			return string(data), err
+		}
+		return utils.ReadFileToString(path)
+	}
+
+	var test Testcase
+	var err error
+	if test.Input, err = read(input); err != nil {
+		return Testcase{}, err
+	}
+	if test.Expected, err = read(output); err != nil {
+		return Testcase{}, err
+	}
+	return test, nil
+}
+
+// editTestcase opens a test case in the user's editor and returns the edited version.
+func editTestcase(test Testcase) (Testcase, error) {
+	file, err := os.CreateTemp("", "fo-test-*.txt")
+	if err != nil {
+		return Testcase{}, err
+	}
+	path := file.Name()
+	defer os.Remove(path)
+
+	_, err = file.WriteString(editorHeader + formatTestcase(1, test))
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return Testcase{}, err
+	}
+
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+	// The editor may carry arguments, e.g. "code --wait"
+	fields := strings.Fields(editor)
+	cmd := exec.Command(fields[0], append(fields[1:], path)...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return Testcase{}, fmt.Errorf("editor %q failed: %w", editor, err)
+	}
+
+	tests, err := parseTestcases(path)
+	if err != nil {
+		return Testcase{}, err
+	}
+	if len(tests) != 1 || strings.TrimSpace(tests[0].Input) == "" {
+		return Testcase{}, fmt.Errorf("no test case written, nothing saved")
+	}
+	return tests[0], nil
+}
+
+// isTerminal reports whether f is an interactive terminal rather than a pipe or file.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// describeSize summarizes the size of a test case part, e.g. "3 lines, 1.2 KB".
+func describeSize(text string) string {
+	lines := len(splitLines(text))
+	size := float64(len(text))
+	switch {
+	case size >= 1<<20:
+		return fmt.Sprintf("%4d line(s), %6.1f MB", lines, size/(1<<20))
+	case size >= 1<<10:
+		return fmt.Sprintf("%4d line(s), %6.1f KB", lines, size/(1<<10))
+	}
+	return fmt.Sprintf("%4d line(s), %6d B ", lines, len(text))
+}
+
+func init() {
+	tcAddCmd.Flags().StringVarP(&tcInput, "input", "i", "", "File holding the input ('-' for stdin)")
+	tcAddCmd.Flags().StringVarP(&tcOutput, "output", "o", "", "File holding the expected output ('-' for stdin)")
+
+	tcCmd.AddCommand(tcAddCmd, tcEditCmd, tcListCmd, tcShowCmd, tcRmCmd, tcRenumberCmd)
+	rootCmd.AddCommand(tcCmd)
+}
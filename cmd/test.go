@@ -2,10 +2,17 @@ package cmd
 
 import (
 	"bufio"
+	"cmp"
+	"context"
 	"fmt"
+	"io"
 	"os"
-	"os/exec"
+	"regexp"
+	"runtime"
+	"slices"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/ahmedYasserM/fo/internal/colors"
 	"github.com/ahmedYasserM/fo/internal/utils"
@@ -14,11 +21,26 @@ import (
 )
 
 type Testcase struct {
-	Input    string
-	Expected string
+	Input    string `yaml:"input" json:"input"`
+	Expected string `yaml:"output,omitempty" json:"output,omitempty"` // empty when the answer is unknown
+	TestMeta `yaml:",inline"`
 }
 
-var testQuiet bool
+var (
+	testQuiet       bool
+	testInteractive bool
+	testTranscript  bool
+	testJobs        int
+	testDiffMode    string
+	testFull        bool
+	testFormat      string
+	testFailed      bool
+	testFailFast    bool
+	testWatch       bool
+)
+
+// defaultInteractor is the interactor source used by --interactive when problem.yaml names none.
+const defaultInteractor = "interactor.cpp"
 
 // parseTestcases reads testcases.txt and extracts input/output samples.
 // It tolerates blank lines and flexible formatting.
@@ -29,30 +51,55 @@ func parseTestcases(filename string) ([]Testcase, error) {
 	}
 	defer file.Close()
 
+	tests, err := readTestcases(file)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %w", filename, err)
+	}
+	return tests, nil
+}
+
+// sampleMarker matches the "--- Sample #N Input ---" and "--- Sample #N Output ---" lines.
+// Input markers may carry metadata: "--- Sample #N Input [label=big; time_limit=5s] ---".
+var sampleMarker = regexp.MustCompile(`^--- Sample.*?(Input|Output)\s*(?:\[(.*)\])?\s*---$`)
+
+// maxTestcaseLine bounds the length of a line of testcases.txt; generated max tests
+// often hold a whole array on one line, far beyond the default of bufio.Scanner.
+const maxTestcaseLine = 1 << 30
+
+// readTestcases extracts the samples delimited by sample markers.
+func readTestcases(r io.Reader) ([]Testcase, error) {
 	var tests []Testcase
-	scanner := bufio.NewScanner(file)
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxTestcaseLine)
 	var state string // "input", "output", or ""
 	var inputLines []string
 	var outputLines []string
+	var meta TestMeta
 
 	for scanner.Scan() {
 		line := scanner.Text()
 		trimmed := strings.TrimSpace(line)
 
-		if strings.HasPrefix(trimmed, "--- Sample") && strings.Contains(trimmed, "Input ---") {
+		marker := sampleMarker.FindStringSubmatch(trimmed)
+		if marker != nil && marker[1] == "Input" {
 			if state != "" {
 				// Save previous test before switching
 				tests = append(tests, Testcase{
 					Input:    strings.Join(inputLines, "\n"),
 					Expected: strings.Join(outputLines, "\n"),
+					TestMeta: meta,
 				})
 				inputLines = nil
 				outputLines = nil
 			}
+			var err error
+			if meta, err = parseTestMeta(marker[2]); err != nil {
+				return nil, err
+			}
 			state = "input"
 			continue
 		}
-		if strings.HasPrefix(trimmed, "--- Sample") && strings.Contains(trimmed, "Output ---") {
+		if marker != nil && marker[1] == "Output" {
 			state = "output"
 			continue
 		}
@@ -72,43 +119,60 @@ func parseTestcases(filename string) ([]Testcase, error) {
 		tests = append(tests, Testcase{
 			Input:    strings.Join(inputLines, "\n"),
 			Expected: strings.Join(outputLines, "\n"),
+			TestMeta: meta,
 		})
 	}
 
 	if err := scanner.Err(); err != nil {
-		return nil, fmt.Errorf("error reading %s: %w", filename, err)
+		return nil, err
 	}
 
 	return tests, nil
 }
 
-// ensureBuilt recompiles source file if missing or outdated
+// formatTestcase renders a test case in the format read by readTestcases.
+func formatTestcase(number int, test Testcase) string {
+	meta := formatTestMeta(test.TestMeta)
+	if meta != "" {
+		meta = " [" + meta + "]"
+	}
+	return fmt.Sprintf("--- Sample #%d Input%s ---\n%s\n\n--- Sample #%d Output ---\n%s\n\n",
+		number, meta, strings.TrimRight(test.Input, " \t\r\n"), number, strings.TrimRight(test.Expected, " \t\r\n"))
+}
+
+// writeTestcases writes test cases to filename in the format read by parseTestcases.
+func writeTestcases(filename string, tests []Testcase) error {
+	var b strings.Builder
+	for i, test := range tests {
+		b.WriteString(formatTestcase(i+1, test))
+	}
+
+	if err := utils.WriteStringToFile(filename, b.String()); err != nil {
+		return fmt.Errorf("failed to write %s: %w", filename, err)
+	}
+	return nil
+}
+
+// ensureBuilt recompiles source file if the executable is missing or was built from
+// a different source, local headers, compiler or flags
 func ensureBuilt(quiet bool) error {
 	if !utils.PathExists(utils.CmdConfig.SourceName) {
 		return fmt.Errorf("%s%s not found.%s", colors.RED, utils.CmdConfig.SourceName, colors.RESET)
 	}
-
-	needsBuild := false
-
-	cppInfo, err := os.Stat(utils.CmdConfig.SourceName)
-	if err != nil {
-		return err
+	if !utils.IsCompiled() {
+		return nil
 	}
 
-	exeInfo, err := os.Stat(utils.CmdConfig.ExecutableName)
-	if os.IsNotExist(err) {
-		needsBuild = true
-	} else if err != nil {
+	needsBuild, err := utils.NeedsBuild(utils.CmdConfig.SourceName, utils.CmdConfig.ExecutableName)
+	if err != nil {
 		return err
-	} else if cppInfo.ModTime().After(exeInfo.ModTime()) {
-		needsBuild = true
 	}
 
 	if needsBuild {
 		if !quiet {
-			fmt.Printf("%s%s changed or executable missing. Rebuilding...%s\n", utils.CmdConfig.SourceName, colors.YELLOW, colors.RESET)
+			fmt.Printf("%s%s, its headers or the compiler settings changed, or the executable is missing. Rebuilding...%s\n", colors.YELLOW, utils.CmdConfig.SourceName, colors.RESET)
 		}
-		if err := utils.BuildExecutable(testQuiet); err != nil {
+		if err := utils.BuildExecutable(quiet); err != nil {
 			return fmt.Errorf("%s❌ %w%s", colors.RED, err, colors.RESET)
 		}
 		if !quiet {
@@ -122,82 +186,539 @@ func ensureBuilt(quiet bool) error {
 	return nil
 }
 
-// testCmd runs tests from testcases.txt by feeding inputs to the program and comparing output
+// testCmd runs tests from the test source by feeding inputs to the program and comparing output
 var testCmd = &cobra.Command{
-	Use:   "test",
+	Use:   "test [N | A-B]...",
 	Short: "Run tests against sample inputs and outputs from testcases.txt",
+	Long: `Runs the solution on every test case, or only the selected ones, and compares its
+output with the expected output. The tests that fail are remembered in ` + stateFile + `
+so that --failed can rerun just them.
+
+Tests are read from the first of these found in the current directory:
+  testcases.txt           samples separated by '--- Sample #N Input/Output ---' markers
+  tests.yaml, tests.json  a list of {input, output} objects
+  tests/1.in, tests/1.out a directory of input files with .out or .ans answers
+  tests/01, tests/01.a    Polygon-style numbered files
+
+Examples:
+  fo test 3 5-7
+  fo test --failed --fail-fast`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 
+		// Machine-readable formats keep stdout clean for their consumers
+		human := testFormat == "human"
+		if !human {
+			if !slices.Contains(reportFormats, testFormat) {
+				return fmt.Errorf("unknown format %q (expected %s)", testFormat, strings.Join(reportFormats, ", "))
+			}
+			testQuiet = true
+		}
+
 		// Load Config
 		if err := utils.LoadConfigOnce(testQuiet); err != nil {
 			return err
 		}
+		if err := applyProfile(cmd); err != nil {
+			return err
+		}
+		if err := applyLanguage(cmd); err != nil {
+			return err
+		}
 
-		// Step 1. Ensure executable is up to date
-		if err := ensureBuilt(testQuiet); err != nil {
+		if testWatch {
+			if !human {
+				return fmt.Errorf("--watch only works with the human format")
+			}
+			watched := func() []string { return append(sourceFiles(), testFiles()...) }
+			return watchLoop(watched, func(ctx context.Context) error {
+				return runTests(ctx, cmd, args)
+			})
+		}
+		return runTests(context.Background(), cmd, args)
+	},
+}
+
+// runTests builds the solution if needed, then runs and reports the tests selected by args.
+func runTests(ctx context.Context, cmd *cobra.Command, args []string) error {
+	human := testFormat == "human"
+
+	// Step 1. Ensure executable is up to date
+	if err := ensureBuilt(testQuiet); err != nil {
+		return err
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	// Step 2. Load test cases and pick the ones to run
+	_, allCases, err := loadTests()
+	if err != nil {
+		return err
+	}
+	selected := allTests(len(allCases))
+	switch {
+	case testFailed && len(args) > 0:
+		return fmt.Errorf("--failed cannot be combined with test selectors")
+	case testFailed:
+		if selected, err = loadFailedTests(len(allCases)); err != nil {
+			return err
+		}
+		if len(selected) == 0 {
+			if !human {
+				// Consumers of the report still expect one, even when there is nothing to run
+				return writeReport(os.Stdout, testFormat, nil)
+			}
+			fmt.Printf("%s✅ No failed tests in the last run.%s\n", colors.GREEN, colors.RESET)
+			return nil
+		}
+	case len(args) > 0:
+		if selected, err = parseSelectors(args, len(allCases)); err != nil {
 			return err
 		}
+	}
+	tests := make([]Testcase, len(selected))
+	for i, index := range selected {
+		tests[i] = allCases[index-1]
+	}
 
-		// Step 2. Parse test cases
-		tests, err := parseTestcases("testcases.txt")
-		if err != nil {
-			return fmt.Errorf("error parsing testcases.txt: %w", err)
+	timeLimit, memoryLimit, err := resolveLimits(cmd)
+	if err != nil {
+		return err
+	}
+	checker, err := resolveChecker(cmd, testQuiet)
+	if err != nil {
+		return err
+	}
+	interactor, err := resolveInteractor(cmd)
+	if err != nil {
+		return err
+	}
+	problem, err := utils.LoadProblem()
+	if err != nil {
+		return err
+	}
+	// Sanitizers and virtual machines reserve more address space than the memory limit allows
+	if reason := utils.MemoryLimitUnsupported(); reason != "" && memoryLimit > 0 {
+		memoryLimit = 0
+		if human {
+			fmt.Printf("%sMemory limit disabled for %s.%s\n", colors.YELLOW, reason, colors.RESET)
+		}
+	}
+
+	// Step 3. Run each test
+	mode := "tests"
+	if interactor != "" {
+		mode = "interactive tests"
+	}
+	if len(tests) < len(allCases) {
+		mode += " " + formatTestNumbers(selected)
+	}
+	if human {
+		memory := fmt.Sprintf("%d MB", memoryLimit)
+		if memoryLimit == 0 {
+			memory = "off"
 		}
-		if len(tests) == 0 {
-			return fmt.Errorf("no tests found in testcases.txt")
+		fmt.Printf("%sRunning %s (time limit %s, memory limit %s)...%s\n", colors.CYAN, mode, timeLimit, memory, colors.RESET)
+		if interactor == "" && (problem.InputFile != "" || problem.OutputFile != "") {
+			fmt.Printf("%sFile I/O: input from %s, output to %s.%s\n", colors.CYAN, cmp.Or(problem.InputFile, "stdin"), cmp.Or(problem.OutputFile, "stdout"), colors.RESET)
 		}
+	}
+
+	runner := &testRunner{
+		opts: utils.RunOptions{
+			TimeLimit:   timeLimit,
+			MemoryLimit: int64(memoryLimit) << 20,
+			OutputLimit: int64(utils.CmdConfig.OutputLimit) << 20,
+			Env:         utils.SanitizerEnv(),
+			InputFile:   problem.InputFile,
+			OutputFile:  problem.OutputFile,
+		},
+		checker:    checker,
+		interactor: interactor,
+		transcript: testTranscript,
+	}
+
+	display := defaultDisplay()
+	if cmd.Flags().Changed("diff") {
+		display.DiffMode = testDiffMode
+	}
+	if display.DiffMode != "unified" && display.DiffMode != "side" {
+		return fmt.Errorf("unknown diff mode %q (expected unified or side)", display.DiffMode)
+	}
+	if testFull {
+		display.Limit = 0
+	}
+
+	tally := verdictTally{}
+	var entries []reportEntry
+	var ran, failed []int
+	runner.runAll(ctx, tests, testJobs, func(i int, result testResult) bool {
+		index := selected[i]
+		if human {
+			printTestResult(index, tests[i], result, display)
+		} else {
+			entries = append(entries, newReportEntry(index, tests[i], result))
+		}
+		if result.Err == nil {
+			tally[result.Verdict]++
+		}
+
+		ran = append(ran, index)
+		if result.Err != nil || !result.Verdict.Passed() {
+			failed = append(failed, index)
+			return !testFailFast
+		}
+		return true
+	})
+
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if err := saveFailedTests(ran, failed); err != nil {
+		return fmt.Errorf("failed to write %s: %w", stateFile, err)
+	}
+
+	if !human {
+		return writeReport(os.Stdout, testFormat, entries)
+	}
+
+	if skipped := len(tests) - len(ran); skipped > 0 {
+		fmt.Printf("%sStopped after the first failure, %d test(s) not run.%s\n", colors.YELLOW, skipped, colors.RESET)
+	}
+	passed := tally[VerdictOK] + tally[VerdictRan]
+	if passed == len(ran) {
+		fmt.Printf("%s✅ Test summary: Passed %d out of %d tests.%s\n", colors.BOLD+colors.CYAN, passed, len(ran), colors.RESET)
+	} else {
+		fmt.Printf("%s❌ Test summary: Passed %d out of %d tests.%s (%s)\n", colors.BOLD+colors.CYAN, passed, len(ran), colors.RESET, tally)
+	}
+	return nil
+}
+
+// oleOutputPreview is how many bytes of a flooded output are shown.
+const oleOutputPreview = 1000
+
+// testResult is the outcome of running a single test case.
+type testResult struct {
+	Verdict     Verdict
+	Message     string // checker or interactor explanation of the verdict
+	Run         *utils.RunResult
+	Transcript  string // interactive dialogue, if recorded
+	Interactive bool
+	Err         error // set when the program could not be run at all
+}
+
+// testRunner runs the executable on test cases and judges the outcomes.
+type testRunner struct {
+	opts       utils.RunOptions
+	checker    Checker
+	interactor string // path to the interactor; empty for standard tests
+	transcript bool   // record interactive dialogues
+}
+
+func (r *testRunner) run(ctx context.Context, test Testcase) testResult {
+	if r.interactor != "" {
+		return r.runInteractive(ctx, test)
+	}
+
+	command, err := r.command()
+	if err != nil {
+		return testResult{Err: err}
+	}
+	opts := r.options(test)
+	opts.Input = test.Input
+	res, err := utils.RunWithLimits(ctx, opts, command[0], command[1:]...)
+	if err != nil {
+		return testResult{Run: res, Err: err}
+	}
+
+	verdict := classifyRun(res)
+	if verdict != VerdictOK {
+		return testResult{Verdict: verdict, Run: res}
+	}
+	if test.answerless() {
+		return testResult{Verdict: VerdictRan, Message: fmt.Sprintf("ran in %.2fs, output not checked", res.Wall.Seconds()), Run: res}
+	}
+	check := r.checker.Check(test.Input, res.Stdout, test.Expected)
+	return testResult{Verdict: check.Verdict, Message: check.Message, Run: res}
+}
 
-		// Step 3. Run each test
-		fmt.Printf("%sRunning tests...%s\n", colors.CYAN, colors.RESET)
+// command returns the command running the solution, with absolute paths when it runs
+// in a directory of its own for file-based I/O.
+func (r *testRunner) command() ([]string, error) {
+	if r.opts.InputFile != "" || r.opts.OutputFile != "" {
+		return utils.AbsRunCommand()
+	}
+	return utils.RunCommand()
+}
 
-		passed := 0
-		for i, test := range tests {
-			actual, err := executeWithInput("./"+utils.CmdConfig.ExecutableName, test.Input)
-			if err != nil {
-				fmt.Printf("%sTest #%d execution error: %v%s\n", colors.RED, i+1, err, colors.RESET)
-				continue
+// options returns the run options for a test, applying its own time limit.
+func (r *testRunner) options(test Testcase) utils.RunOptions {
+	opts := r.opts
+	if test.TimeLimit != "" {
+		opts.TimeLimit, _ = time.ParseDuration(test.TimeLimit) // checked by loadTests
+	}
+	return opts
+}
+
+// runAll runs the tests on up to jobs workers at once (all CPUs if jobs <= 0),
+// calling report for each result in test order as soon as it is available.
+// Returning false from report, or cancelling ctx, stops the run; tests already running are
+// killed and not reported.
+func (r *testRunner) runAll(ctx context.Context, tests []Testcase, jobs int, report func(i int, result testResult) bool) {
+	if jobs <= 0 {
+		jobs = runtime.NumCPU()
+	}
+
+	// Nothing would kill the tests still running at their time limit once we exit,
+	// so kill them and wait for them before returning
+	ctx, cancel := context.WithCancel(ctx)
+	var workers sync.WaitGroup
+	defer workers.Wait()
+	defer cancel()
+
+	results := make([]chan testResult, len(tests))
+	for i := range results {
+		results[i] = make(chan testResult, 1)
+	}
+
+	queue := make(chan int)
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		defer close(queue)
+		for i := range tests {
+			select {
+			case queue <- i:
+			case <-stop:
+				return
 			}
-			actual = strings.TrimSpace(actual)
-
-			if actual == strings.TrimSpace(test.Expected) {
-				fmt.Printf("%s=== Test %d === %s[OK]%s\n", colors.BOLD, i+1, colors.GREEN, colors.RESET)
-				passed++
-			} else {
-				fmt.Printf("%s=== Test %d === %s[FAIL]%s\n", colors.BOLD, i+1, colors.RED, colors.RESET)
-				fmt.Printf("%sInput:%s\n%s\n", colors.YELLOW, colors.RESET, strings.TrimSpace(test.Input))
-				fmt.Printf("%sYour output:%s\n%s\n", colors.YELLOW, colors.RESET, strings.TrimSpace(actual))
-				fmt.Printf("%sExpected:%s\n%s\n\n", colors.YELLOW, colors.RESET, strings.TrimSpace(test.Expected))
+		}
+	}()
+	for range min(jobs, len(tests)) {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for i := range queue {
+				results[i] <- r.run(ctx, tests[i])
 			}
+		}()
+	}
 
+	for i := range tests {
+		select {
+		case result := <-results[i]:
+			if !report(i, result) {
+				return
+			}
+		case <-ctx.Done():
+			return
 		}
+	}
+}
 
-		if passed == len(tests) {
-			fmt.Printf("%s✅ Test summary: Passed %d out of %d tests.%s\n", colors.BOLD+colors.CYAN, passed, len(tests), colors.RESET)
-		} else {
-			fmt.Printf("%s❌ Test summary: Passed %d out of %d tests.%s\n", colors.BOLD+colors.CYAN, passed, len(tests), colors.RESET)
-		}
-		return nil
-	},
+// runInteractive runs the executable against the interactor, which is invoked testlib-style
+// as "interactor input output answer" and decides the verdict with its exit code.
+func (r *testRunner) runInteractive(ctx context.Context, test Testcase) testResult {
+	dir, files, err := writeTestlibFiles(test.Input, "", test.Expected)
+	if err != nil {
+		return testResult{Err: err}
+	}
+	defer os.RemoveAll(dir)
+
+	command, err := utils.RunCommand()
+	if err != nil {
+		return testResult{Err: err}
+	}
+	res, err := utils.RunInteractive(ctx, r.options(test),
+		command,
+		append([]string{r.interactor}, files...),
+		r.transcript)
+	if err != nil {
+		return testResult{Err: err}
+	}
+
+	result := testResult{Run: res.Solution, Transcript: res.Transcript, Interactive: true}
+	if res.Solution.TimedOut {
+		result.Verdict = VerdictTLE
+		return result
+	}
+
+	message := strings.TrimSpace(res.Interactor.Stderr)
+	if res.Interactor.TimedOut || res.Interactor.Signal != "" {
+		result.Verdict, result.Message = VerdictFail, "interactor did not finish normally: "+message
+		return result
+	}
+	check := testlibVerdict(res.Interactor.ExitCode, message)
+	result.Verdict, result.Message = check.Verdict, check.Message
+	// A crashing solution usually makes the interactor complain about EOF,
+	// so the crash itself is the more useful verdict
+	if verdict := classifyRun(res.Solution); verdict != VerdictOK && check.Verdict != VerdictFail {
+		result.Verdict = verdict
+	}
+	return result
 }
 
-// executeWithInput runs a command feeding its stdin and returns the stdout output (or error)
-func executeWithInput(command, input string) (string, error) {
-	cmd := exec.Command(command)
-	cmd.Stdin = strings.NewReader(input)
+// printTestResult reports a single test, with details for everything but OK.
+func printTestResult(index int, test Testcase, result testResult, display displayOptions) {
+	if result.Err != nil {
+		fmt.Printf("%sTest #%d execution error: %v%s\n", colors.RED, index, result.Err, colors.RESET)
+		return
+	}
+
+	res := result.Run
+	output := strings.TrimSpace(res.Stdout)
+	fmt.Printf("%s=== %s === %s[%s]%s %s\n", colors.BOLD, test.name(index), result.Verdict.Color(), result.Verdict, colors.RESET, formatUsage(res))
 
-	outBytes, err := cmd.Output()
+	switch result.Verdict {
+	case VerdictRan:
+		fmt.Printf("%sNo expected output: %s%s\n", colors.CYAN, result.Message, colors.RESET)
+		return
+	case VerdictOK, VerdictTLE, VerdictMLE:
+		if result.Transcript != "" {
+			fmt.Printf("%sTranscript:%s\n%s\n\n", colors.YELLOW, colors.RESET, result.Transcript)
+		}
+		return
+	case VerdictRE:
+		fmt.Printf("%sRuntime error:%s %s\n", colors.YELLOW, colors.RESET, runtimeErrorReason(res))
+		if stderr := strings.TrimSpace(res.Stderr); stderr != "" {
+			title := "Stderr"
+			if sanitizerSummary(stderr) != "" {
+				title = "Sanitizer report"
+			}
+			fmt.Printf("%s%s:%s\n", colors.YELLOW, title, colors.RESET)
+			printTruncated(stderr, display.Limit)
+		}
+		if result.Message != "" {
+			fmt.Printf("%sInteractor:%s %s\n", colors.YELLOW, colors.RESET, result.Message)
+		}
+	case VerdictWA, VerdictPE, VerdictFail:
+		judge := "Checker"
+		if result.Interactive {
+			judge = "Interactor"
+		}
+		fmt.Printf("%s%s:%s %s\n", colors.YELLOW, judge, colors.RESET, result.Message)
+	case VerdictOLE:
+		fmt.Printf("%sOutput limit of %d MB exceeded, showing the beginning of the output.%s\n", colors.YELLOW, utils.CmdConfig.OutputLimit, colors.RESET)
+		if len(output) > oleOutputPreview {
+			output = output[:oleOutputPreview] + "..."
+		}
+	}
+
+	fmt.Printf("%sInput:%s\n", colors.YELLOW, colors.RESET)
+	printTruncated(strings.TrimSpace(test.Input), display.Limit)
+	if result.Interactive {
+		if result.Transcript != "" {
+			fmt.Printf("%sTranscript:%s\n%s\n", colors.YELLOW, colors.RESET, result.Transcript)
+		}
+		fmt.Println()
+		return
+	}
+	printDiff(output, test.Expected, display)
+	fmt.Println()
+}
+
+// resolveLimits picks the time and memory (in megabytes) limits for a test run.
+// The --time-limit and --memory-limit flags of cmd win over problem.yaml,
+// which wins over the config defaults.
+func resolveLimits(cmd *cobra.Command) (time.Duration, int, error) {
+	problem, err := utils.LoadProblem()
 	if err != nil {
-		// Try to get stderr text in case of exec error
-		if exitErr, ok := err.(*exec.ExitError); ok {
-			return "", fmt.Errorf("program error: %s", string(exitErr.Stderr))
+		return 0, 0, err
+	}
+
+	timeLimit := utils.CmdConfig.TimeLimit
+	if cmd.Flags().Changed("time-limit") {
+		timeLimit, _ = cmd.Flags().GetDuration("time-limit")
+	} else if problem.TimeLimit > 0 {
+		timeLimit = problem.TimeLimit
+	}
+
+	memoryLimit := utils.CmdConfig.MemoryLimit
+	if cmd.Flags().Changed("memory-limit") {
+		memoryLimit, _ = cmd.Flags().GetInt("memory-limit")
+	} else if problem.MemoryLimit > 0 {
+		memoryLimit = problem.MemoryLimit
+	}
+
+	return timeLimit, memoryLimit, nil
+}
+
+// resolveChecker builds the output checker for a test run.
+// The --checker flag of cmd wins over problem.yaml, which wins over the config default.
+func resolveChecker(cmd *cobra.Command, quiet bool) (Checker, error) {
+	spec := utils.CmdConfig.Checker
+	if cmd.Flags().Changed("checker") {
+		spec, _ = cmd.Flags().GetString("checker")
+	} else {
+		problem, err := utils.LoadProblem()
+		if err != nil {
+			return nil, err
+		}
+		if problem.Checker != "" {
+			spec = problem.Checker
 		}
+	}
+	return parseChecker(spec, quiet)
+}
+
+// resolveInteractor prepares the interactor when running in interactive mode, which is
+// enabled by --interactive or by an interactor named in problem.yaml.
+// It returns an empty path for standard tests.
+func resolveInteractor(cmd *cobra.Command) (string, error) {
+	problem, err := utils.LoadProblem()
+	if err != nil {
 		return "", err
 	}
-	return string(outBytes), nil
+
+	path := problem.Interactor
+	if path == "" {
+		if !testInteractive {
+			return "", nil
+		}
+		path = defaultInteractor
+	}
+	if !utils.PathExists(path) {
+		return "", fmt.Errorf("interactor %s not found", path)
+	}
+
+	path, err = utils.PrepareProgram(path, testQuiet)
+	if err != nil {
+		return "", fmt.Errorf("failed to build interactor: %w", err)
+	}
+	return path, nil
+}
+
+// formatUsage renders the wall time, CPU time and peak memory of a run
+func formatUsage(res *utils.RunResult) string {
+	return fmt.Sprintf("(%.3fs wall, %.3fs cpu, %.1f MB)", res.Wall.Seconds(), res.CPU.Seconds(), float64(res.Memory)/(1<<20))
+}
+
+// addLimitFlags registers the flags read by resolveLimits.
+func addLimitFlags(cmd *cobra.Command) {
+	cmd.Flags().DurationP("time-limit", "t", 0, "Time limit per test (e.g. 2s, 500ms); 0 disables it")
+	cmd.Flags().IntP("memory-limit", "m", 0, "Memory limit per test in megabytes; 0 disables it")
+}
+
+// addCheckerFlag registers the flag read by resolveChecker.
+func addCheckerFlag(cmd *cobra.Command) {
+	cmd.Flags().StringP("checker", "c", "", "Output checker: lines, tokens, nocase, float[:epsilon] or a checker program/source")
 }
 
 func init() {
 	testCmd.Flags().BoolVarP(&testQuiet, "quiet", "q", false, "Suppress build output during tests")
+	testCmd.Flags().IntVarP(&testJobs, "jobs", "j", runtime.NumCPU(), "Number of tests run in parallel; use 1 for the most reliable timings")
+	testCmd.Flags().StringVar(&testDiffMode, "diff", "unified", "Diff layout for failing tests: unified or side")
+	testCmd.Flags().StringVarP(&testFormat, "format", "f", "human", "Output format: "+strings.Join(reportFormats, ", "))
+	testCmd.Flags().BoolVar(&testFull, "full", false, "Show inputs and outputs of failing tests without truncation")
+	addLimitFlags(testCmd)
+	testCmd.Flags().BoolVarP(&testInteractive, "interactive", "i", false, "Run against an interactor (default: "+defaultInteractor+")")
+	testCmd.Flags().BoolVar(&testTranscript, "transcript", false, "Show the full dialogue of interactive tests")
+	testCmd.Flags().BoolVar(&testFailed, "failed", false, "Run only the tests that failed in the last run")
+	testCmd.Flags().BoolVar(&testFailFast, "fail-fast", false, "Stop at the first failing test")
+	addProfileFlag(testCmd)
+	addLangFlag(testCmd)
+	testCmd.Flags().BoolVarP(&testWatch, "watch", "w", false, "Rebuild and rerun the tests whenever the source, its headers or the tests change")
+	addCheckerFlag(testCmd)
 
 	rootCmd.AddCommand(testCmd)
 }
@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// expectRuns is the TestMeta.Expect value for tests that only need to finish
+// without a runtime error or exceeding a limit; their output is not compared.
+const expectRuns = "runs"
+
+// TestMeta is the optional metadata of a test case.
+type TestMeta struct {
+	Label     string `yaml:"label,omitempty" json:"label,omitempty"`
+	TimeLimit string `yaml:"time_limit,omitempty" json:"time_limit,omitempty"` // overrides the time limit, e.g. 5s
+	Expect    string `yaml:"expect,omitempty" json:"expect,omitempty"`         // "runs" to skip comparing the output
+}
+
+// validate checks the values of the metadata.
+func (m TestMeta) validate() error {
+	// Labels go on testcases.txt markers as "label=...; ...", on a single line
+	if strings.ContainsAny(m.Label, ";=\r\n") {
+		return fmt.Errorf("invalid label %q (it cannot contain ';', '=' or line breaks)", m.Label)
+	}
+	if m.TimeLimit != "" {
+		if d, err := time.ParseDuration(m.TimeLimit); err != nil || d < 0 {
+			return fmt.Errorf("invalid time limit %q", m.TimeLimit)
+		}
+	}
+	if m.Expect != "" && m.Expect != expectRuns {
+		return fmt.Errorf("invalid expect %q (expected %s)", m.Expect, expectRuns)
+	}
+	return nil
+}
+
+// answerless reports whether the output of the test is not compared, either because
+// the test only checks that the program runs or because its answer is unknown.
+func (t Testcase) answerless() bool {
+	return t.Expect == expectRuns || strings.TrimSpace(t.Expected) == ""
+}
+
+// name is the title of the test in reports, e.g. "Test 3 (max test)".
+func (t Testcase) name(index int) string {
+	if t.Label != "" {
+		return fmt.Sprintf("Test %d (%s)", index, t.Label)
+	}
+	return fmt.Sprintf("Test %d", index)
+}
+
+// parseTestMeta parses the "key=value; key=value" metadata of a testcases.txt marker.
+func parseTestMeta(text string) (TestMeta, error) {
+	var meta TestMeta
+	for _, field := range strings.Split(text, ";") {
+		if strings.TrimSpace(field) == "" {
+			continue
+		}
+		key, value, _ := strings.Cut(field, "=")
+		value = strings.TrimSpace(value)
+		switch strings.TrimSpace(key) {
+		case "label":
+			meta.Label = value
+		case "time_limit":
+			meta.TimeLimit = value
+		case "expect":
+			meta.Expect = value
+		default:
+			return TestMeta{}, fmt.Errorf("unknown test metadata %q (expected label, time_limit or expect)", strings.TrimSpace(key))
+		}
+	}
+	return meta, meta.validate()
+}
+
+// formatTestMeta renders metadata in the format read by parseTestMeta.
+func formatTestMeta(meta TestMeta) string {
+	var fields []string
+	if meta.Label != "" {
+		fields = append(fields, "label="+meta.Label)
+	}
+	if meta.TimeLimit != "" {
+		fields = append(fields, "time_limit="+meta.TimeLimit)
+	}
+	if meta.Expect != "" {
+		fields = append(fields, "expect="+meta.Expect)
+	}
+	return strings.Join(fields, "; ")
+}
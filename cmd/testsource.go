@@ -0,0 +1,370 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ahmedYasserM/fo/internal/utils"
+	"gopkg.in/yaml.v3"
+)
+
+// TestSource stores the test cases of a problem in one of the supported layouts.
+type TestSource interface {
+	// Name is the file or directory holding the tests, used in messages.
+	Name() string
+	// Exists reports whether tests in this layout are present in the current directory.
+	Exists() bool
+	Load() ([]Testcase, error)
+	Save(tests []Testcase) error
+}
+
+// testsDir is the directory used by the per-file test layouts.
+const testsDir = "tests"
+
+// testFormats maps the test_format config values to their layouts,
+// in the order they are tried when detecting existing tests.
+var testFormats = []struct {
+	name   string
+	source TestSource
+}{
+	{"txt", txtSource{"testcases.txt"}},
+	{"yaml", structuredSource{"tests.yaml", yaml.Marshal, yaml.Unmarshal}},
+	{"json", structuredSource{"tests.json", marshalIndentJSON, json.Unmarshal}},
+	{"dir", dirSource{testsDir}},
+	{"polygon", polygonSource{testsDir}},
+}
+
+// detectTestSource returns the layout of the tests in the current directory,
+// or the configured layout when there are none yet.
+func detectTestSource() (TestSource, error) {
+	for _, f := range testFormats {
+		if f.source.Exists() {
+			return f.source, nil
+		}
+	}
+	return configuredTestSource()
+}
+
+// configuredTestSource returns the layout selected by test_format in the config.
+func configuredTestSource() (TestSource, error) {
+	var names []string
+	for _, f := range testFormats {
+		if f.name == utils.CmdConfig.TestFormat {
+			return f.source, nil
+		}
+		names = append(names, f.name)
+	}
+	return nil, fmt.Errorf("unknown test format %q (expected %s)", utils.CmdConfig.TestFormat, strings.Join(names, ", "))
+}
+
+// configuredTestSourceIn is like configuredTestSource for the problem in directory dir.
+func configuredTestSourceIn(dir string) (TestSource, error) {
+	source, err := configuredTestSource()
+	if err != nil {
+		return nil, err
+	}
+	switch s := source.(type) {
+	case txtSource:
+		s.filename = filepath.Join(dir, s.filename)
+		return s, nil
+	case structuredSource:
+		s.filename = filepath.Join(dir, s.filename)
+		return s, nil
+	case dirSource:
+		s.dir = filepath.Join(dir, s.dir)
+		return s, nil
+	case polygonSource:
+		s.dir = filepath.Join(dir, s.dir)
+		return s, nil
+	}
+	return nil, fmt.Errorf("test format %q cannot be used in another directory", utils.CmdConfig.TestFormat)
+}
+
+// loadTests loads the tests of the current directory, failing if there are none.
+func loadTests() (TestSource, []Testcase, error) {
+	source, err := detectTestSource()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tests, err := source.Load()
+	if err != nil {
+		return nil, nil, fmt.Errorf("error parsing %s: %w", source.Name(), err)
+	}
+	if len(tests) == 0 {
+		return nil, nil, fmt.Errorf("no tests found in %s", source.Name())
+	}
+	for i, test := range tests {
+		if err := test.validate(); err != nil {
+			return nil, nil, fmt.Errorf("test #%d in %s: %w", i+1, source.Name(), err)
+		}
+	}
+	return source, tests, nil
+}
+
+// parseTestNumber parses a 1-based test number among count tests.
+func parseTestNumber(arg string, count int) (int, error) {
+	index, err := strconv.Atoi(arg)
+	if err != nil || index < 1 || index > count {
+		return 0, fmt.Errorf("invalid test number %q (expected 1 to %d)", arg, count)
+	}
+	return index, nil
+}
+
+// appendTestcase adds a test case after the existing ones, creating the source if needed.
+// It returns the number of the new test case.
+func appendTestcase(source TestSource, test Testcase) (int, error) {
+	var tests []Testcase
+	if source.Exists() {
+		var err error
+		if tests, err = source.Load(); err != nil {
+			return 0, err
+		}
+	}
+
+	tests = append(tests, test)
+	return len(tests), source.Save(tests)
+}
+
+// txtSource is the testcases.txt layout with "--- Sample #N Input/Output ---" markers.
+type txtSource struct {
+	filename string
+}
+
+func (s txtSource) Name() string                { return s.filename }
+func (s txtSource) Exists() bool                { return utils.PathExists(s.filename) }
+func (s txtSource) Load() ([]Testcase, error)   { return parseTestcases(s.filename) }
+func (s txtSource) Save(tests []Testcase) error { return writeTestcases(s.filename, tests) }
+
+// structuredSource is a YAML or JSON list of {input, output} objects.
+type structuredSource struct {
+	filename  string
+	marshal   func(any) ([]byte, error)
+	unmarshal func([]byte, any) error
+}
+
+func (s structuredSource) Name() string { return s.filename }
+func (s structuredSource) Exists() bool { return utils.PathExists(s.filename) }
+
+func (s structuredSource) Load() ([]Testcase, error) {
+	data, err := utils.ReadFileToBytes(s.filename)
+	if err != nil {
+		return nil, err
+	}
+	var tests []Testcase
+	if err := s.unmarshal(data, &tests); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", s.filename, err)
+	}
+	return tests, nil
+}
+
+func (s structuredSource) Save(tests []Testcase) error {
+	data, err := s.marshal(tests)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.filename, data, 0o644)
+}
+
+func marshalIndentJSON(v any) ([]byte, error) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	return append(data, '\n'), err
+}
+
+// numberedFiles lists the files of dir whose names match pattern, whose first
+// submatch is the test number, sorted by that number.
+func numberedFiles(dir string, pattern *regexp.Regexp) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, e := range entries {
+		if !e.IsDir() && pattern.MatchString(e.Name()) {
+			names = append(names, e.Name())
+		}
+	}
+	number := func(name string) int {
+		n, _ := strconv.Atoi(pattern.FindStringSubmatch(name)[1])
+		return n
+	}
+	sort.Slice(names, func(i, j int) bool { return number(names[i]) < number(names[j]) })
+	return names, nil
+}
+
+// removeMatching deletes the files of dir whose names match pattern.
+func removeMatching(dir string, pattern *regexp.Regexp) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if !e.IsDir() && pattern.MatchString(e.Name()) {
+			if err := os.Remove(filepath.Join(dir, e.Name())); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// metaSuffix names the file holding the metadata of a test next to its input, e.g. 1.meta.yaml.
+const metaSuffix = ".meta.yaml"
+
+// readPair reads an input file, its metadata file if any, and the first existing answer
+// file among candidates. Without an answer file, the test has no expected output.
+func readPair(dir, input, meta string, answers ...string) (Testcase, error) {
+	in, err := utils.ReadFileToString(filepath.Join(dir, input))
+	if err != nil {
+		return Testcase{}, err
+	}
+	test := Testcase{Input: in}
+	if path := filepath.Join(dir, meta); utils.PathExists(path) {
+		data, err := utils.ReadFileToBytes(path)
+		if err != nil {
+			return Testcase{}, err
+		}
+		if err := yaml.Unmarshal(data, &test.TestMeta); err != nil {
+			return Testcase{}, fmt.Errorf("failed to parse %s: %w", meta, err)
+		}
+	}
+	for _, answer := range answers {
+		path := filepath.Join(dir, answer)
+		if utils.PathExists(path) {
+			test.Expected, err = utils.ReadFileToString(path)
+			return test, err
+		}
+	}
+	return test, nil
+}
+
+// writePair writes an input file, the metadata file if the test has metadata, and,
+// if the answer is known, its answer file.
+func writePair(input, answer, meta string, test Testcase) error {
+	if err := utils.WriteStringToFile(input, withTrailingNewline(test.Input)); err != nil {
+		return err
+	}
+	if test.TestMeta != (TestMeta{}) {
+		data, err := yaml.Marshal(test.TestMeta)
+		if err != nil {
+			return err
+		}
+		if err := utils.WriteStringToFile(meta, string(data)); err != nil {
+			return err
+		}
+	}
+	if strings.TrimSpace(test.Expected) == "" {
+		return nil
+	}
+	return utils.WriteStringToFile(answer, withTrailingNewline(test.Expected))
+}
+
+var (
+	dirInputPattern  = regexp.MustCompile(`^(\d+)\.in$`)
+	dirFilePattern   = regexp.MustCompile(`^(\d+)\.(in|out|ans|meta\.yaml)$`)
+	polygonPattern   = regexp.MustCompile(`^(\d+)$`)
+	polygonAllFiles  = regexp.MustCompile(`^(\d+)(\.a|\.meta\.yaml)?$`)
+	polygonNameWidth = 2
+)
+
+// dirSource is a directory of numbered N.in files with N.out or N.ans answers.
+type dirSource struct {
+	dir string
+}
+
+func (s dirSource) Name() string { return s.dir + "/" }
+
+func (s dirSource) Exists() bool {
+	names, err := numberedFiles(s.dir, dirInputPattern)
+	return err == nil && len(names) > 0
+}
+
+func (s dirSource) Load() ([]Testcase, error) {
+	names, err := numberedFiles(s.dir, dirInputPattern)
+	if err != nil {
+		return nil, err
+	}
+
+	var tests []Testcase
+	for _, name := range names {
+		stem := strings.TrimSuffix(name, ".in")
+		test, err := readPair(s.dir, name, stem+metaSuffix, stem+".out", stem+".ans")
+		if err != nil {
+			return nil, err
+		}
+		tests = append(tests, test)
+	}
+	return tests, nil
+}
+
+func (s dirSource) Save(tests []Testcase) error {
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return err
+	}
+	if err := removeMatching(s.dir, dirFilePattern); err != nil {
+		return err
+	}
+	for i, test := range tests {
+		base := filepath.Join(s.dir, strconv.Itoa(i+1))
+		if err := writePair(base+".in", base+".out", base+metaSuffix, test); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// polygonSource is the Polygon layout: numbered input files (01, 02, ...) with .a answers.
+type polygonSource struct {
+	dir string
+}
+
+func (s polygonSource) Name() string { return s.dir + "/" }
+
+func (s polygonSource) Exists() bool {
+	names, err := numberedFiles(s.dir, polygonPattern)
+	return err == nil && len(names) > 0
+}
+
+func (s polygonSource) Load() ([]Testcase, error) {
+	names, err := numberedFiles(s.dir, polygonPattern)
+	if err != nil {
+		return nil, err
+	}
+
+	var tests []Testcase
+	for _, name := range names {
+		test, err := readPair(s.dir, name, name+metaSuffix, name+".a")
+		if err != nil {
+			return nil, err
+		}
+		tests = append(tests, test)
+	}
+	return tests, nil
+}
+
+func (s polygonSource) Save(tests []Testcase) error {
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return err
+	}
+	if err := removeMatching(s.dir, polygonAllFiles); err != nil {
+		return err
+	}
+	for i, test := range tests {
+		base := filepath.Join(s.dir, fmt.Sprintf("%0*d", polygonNameWidth, i+1))
+		if err := writePair(base, base+".a", base+metaSuffix, test); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func withTrailingNewline(text string) string {
+	return strings.TrimRight(text, " \t\r\n") + "\n"
+}
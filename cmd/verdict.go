@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ahmedYasserM/fo/internal/colors"
+	"github.com/ahmedYasserM/fo/internal/utils"
+)
+
+// Verdict is the judgement of a single test run.
+type Verdict string
+
+const (
+	VerdictOK   Verdict = "OK"
+	VerdictWA   Verdict = "WA"
+	VerdictRE   Verdict = "RE"
+	VerdictTLE  Verdict = "TLE"
+	VerdictMLE  Verdict = "MLE"
+	VerdictOLE  Verdict = "OLE"
+	VerdictPE   Verdict = "PE"   // presentation error, reported by checker programs
+	VerdictFail Verdict = "FAIL" // the checker itself failed
+	VerdictRan  Verdict = "RAN"  // finished without errors, output not checked
+)
+
+// verdictOrder is the order in which verdicts are listed in summaries.
+var verdictOrder = []Verdict{VerdictOK, VerdictRan, VerdictWA, VerdictPE, VerdictRE, VerdictTLE, VerdictMLE, VerdictOLE, VerdictFail}
+
+// Color returns the terminal color used to print the verdict.
+func (v Verdict) Color() string {
+	switch v {
+	case VerdictOK:
+		return colors.GREEN
+	case VerdictRan:
+		return colors.CYAN
+	case VerdictWA, VerdictPE, VerdictRE:
+		return colors.RED
+	case VerdictFail:
+		return colors.YELLOW
+	default:
+		return colors.MAGENTA
+	}
+}
+
+// Passed reports whether the verdict counts as a passing test.
+func (v Verdict) Passed() bool {
+	return v == VerdictOK || v == VerdictRan
+}
+
+// signalReasons explains the usual causes of fatal signals in contest solutions.
+var signalReasons = map[string]string{
+	"SIGSEGV": "segmentation fault (out-of-bounds access, null pointer or stack overflow)",
+	"SIGFPE":  "floating point exception (integer division or modulo by zero)",
+	"SIGABRT": "aborted (failed assert, uncaught exception or heap corruption)",
+	"SIGBUS":  "bus error (misaligned or invalid memory access)",
+	"SIGILL":  "illegal instruction (often a missing return in a non-void function)",
+	"SIGKILL": "killed",
+	"SIGPIPE": "broken pipe",
+	"SIGXCPU": "CPU time limit exceeded",
+	"SIGXFSZ": "file size limit exceeded",
+	"SIGTRAP": "trap (sanitizer or debugger breakpoint)",
+}
+
+// classifyRun decides the verdict of a finished run, without comparing its output.
+// It returns VerdictOK when the program exited normally and the output should be checked.
+func classifyRun(res *utils.RunResult) Verdict {
+	switch {
+	case res.OutputExceeded:
+		return VerdictOLE
+	case res.TimedOut:
+		return VerdictTLE
+	case res.MemoryExceeded:
+		return VerdictMLE
+	case res.Failed():
+		return VerdictRE
+	}
+	return VerdictOK
+}
+
+// runtimeErrorReason describes why a run ended with a runtime error.
+func runtimeErrorReason(res *utils.RunResult) string {
+	if summary := sanitizerSummary(res.Stderr); summary != "" {
+		return summary
+	}
+	if res.Signal == "" {
+		return fmt.Sprintf("exit code %d", res.ExitCode)
+	}
+	if reason, ok := signalReasons[res.Signal]; ok {
+		return fmt.Sprintf("%s: %s", res.Signal, reason)
+	}
+	return res.Signal
+}
+
+// sanitizerSummary returns the one-line description of the first error reported by
+// AddressSanitizer or UndefinedBehaviorSanitizer in stderr, or "" if there is none.
+func sanitizerSummary(stderr string) string {
+	var summary string
+	for _, line := range strings.Split(stderr, "\n") {
+		// UBSan: "main.cpp:5:7: runtime error: signed integer overflow: ..."
+		if _, message, ok := strings.Cut(line, "runtime error: "); ok {
+			return "UndefinedBehaviorSanitizer: " + strings.TrimSpace(message)
+		}
+		// ASan: "SUMMARY: AddressSanitizer: heap-buffer-overflow main.cpp:5 in main"
+		if rest, ok := strings.CutPrefix(strings.TrimSpace(line), "SUMMARY: "); ok && summary == "" {
+			summary = rest
+		}
+	}
+	return summary
+}
+
+// verdictTally counts how many tests got each verdict.
+type verdictTally map[Verdict]int
+
+// String lists the non-zero counts in a stable order, e.g. "OK 3, WA 1".
+func (t verdictTally) String() string {
+	var parts []string
+	for _, v := range verdictOrder {
+		if t[v] > 0 {
+			parts = append(parts, fmt.Sprintf("%s%s %d%s", v.Color(), v, t[v], colors.RESET))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/ahmedYasserM/fo/internal/colors"
+	"github.com/ahmedYasserM/fo/internal/utils"
+)
+
+// watchDebounce is how long to wait for further saves before rerunning.
+const watchDebounce = 150 * time.Millisecond
+
+// watchLoop runs action, then reruns it whenever one of the paths returned by paths changes,
+// cancelling the previous run if it is still going. It returns on Ctrl+C.
+func watchLoop(paths func() []string, action func(ctx context.Context) error) error {
+	watcher, err := utils.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	changes := watcher.Changes(ctx, watchDebounce)
+
+	for {
+		// Recomputed every time so that newly included headers are watched
+		if err := watcher.Watch(paths()); err != nil {
+			return err
+		}
+		fmt.Print(colors.CLEAR_SCREEN)
+
+		runCtx, cancel := context.WithCancel(ctx)
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			err := action(runCtx)
+			if runCtx.Err() != nil {
+				return
+			}
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "%s❌ %v%s\n", colors.RED, err, colors.RESET)
+			}
+			fmt.Printf("%sWatching for changes, press Ctrl+C to stop...%s\n", colors.CYAN, colors.RESET)
+		}()
+
+		_, ok := <-changes
+		cancel()
+		<-done
+		if !ok {
+			return nil
+		}
+	}
+}
+
+// sourceFiles lists the source file and the local headers it includes.
+func sourceFiles() []string {
+	files := []string{utils.CmdConfig.SourceName}
+	headers, err := utils.LocalIncludes(utils.CmdConfig.SourceName)
+	if err != nil {
+		// The source is missing; watching it still catches its creation
+		return files
+	}
+	return append(files, headers...)
+}
+
+// testFiles lists every file and directory tests may be read from, plus problem.yaml.
+func testFiles() []string {
+	files := []string{utils.ProblemFile}
+	for _, f := range testFormats {
+		files = append(files, strings.TrimSuffix(f.source.Name(), "/"))
+	}
+	return files
+}
@@ -1,11 +1,14 @@
 package colors
 
 const (
-	RED     = "\033[1;31m"
-	GREEN   = "\033[1;32m"
-	YELLOW  = "\033[1;33m"
-	MAGENTA = "\033[1;35m"
-	CYAN    = "\033[1;36m"
-	BOLD    = "\033[1m"
-	RESET   = "\033[0m"
+	RED       = "\033[1;31m"
+	GREEN     = "\033[1;32m"
+	YELLOW    = "\033[1;33m"
+	MAGENTA   = "\033[1;35m"
+	CYAN      = "\033[1;36m"
+	BOLD      = "\033[1m"
+	UNDERLINE = "\033[4m"
+	RESET     = "\033[0m"
+
+	CLEAR_SCREEN = "\033[H\033[2J"
 )
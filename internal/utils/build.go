@@ -1,28 +1,179 @@
 package utils
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/ahmedYasserM/fo/internal/colors"
+	"gopkg.in/yaml.v3"
 )
 
-// buildExecutable encapsulates the C++ build logic.
+// buildExecutable encapsulates the build logic of the solution.
 // It returns an error if the build fails.
 func BuildExecutable(quiet bool) error {
-	args := strings.Fields(CmdConfig.Compiler.Flags)
-	args = append(args, CmdConfig.SourceName, "-o", CmdConfig.ExecutableName)
+	return BuildSource(CmdConfig.SourceName, CmdConfig.ExecutableName, quiet)
+}
+
+// BuildSource compiles any source (e.g. a checker) with the compile command of its language,
+// which for C++ uses the configured compiler and flags. Interpreted languages need no build.
+func BuildSource(source, executable string, quiet bool) error {
+	if !PathExists(source) {
+		return fmt.Errorf("%s not found. Cannot compile.", source)
+	}
+	name, lang, err := LanguageOf(source)
+	if err != nil {
+		return err
+	}
+	if lang.Compile == "" {
+		return nil
+	}
+	args := compileArgs(name, lang, source, executable)
 
-	if !PathExists(CmdConfig.SourceName) {
-		return fmt.Errorf("%s not found. Cannot compile.", CmdConfig.SourceName)
+	// A failed precompilation only costs speed, the build goes on with the plain header
+	if pchIncludeDir(name, source) != "" && !PchBuilt() {
+		if _, err := BuildPch(quiet); err != nil {
+			fmt.Fprintf(os.Stderr, "%s⚠️ %v%s\n", colors.YELLOW, err, colors.RESET)
+		}
 	}
 
 	if !quiet {
-		fmt.Printf("Compiling %s%s%s...\n", colors.CYAN, CmdConfig.SourceName, colors.RESET)
+		if ActiveProfile != "" {
+			fmt.Printf("Compiling %s%s%s with the %s profile...\n", colors.CYAN, source, colors.RESET, ActiveProfile)
+		} else {
+			fmt.Printf("Compiling %s%s%s...\n", colors.CYAN, source, colors.RESET)
+		}
+	}
+	// Fingerprint the inputs before compiling, so edits made meanwhile trigger another build
+	manifest, err := newBuildManifest(source, args)
+	if err != nil {
+		return err
+	}
+	// A stale manifest must not outlive a failed build
+	if err := os.Remove(ManifestPath(executable)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	err = ExecuteCmd(args[0], args[1:]...)
+	if err != nil {
+		return fmt.Errorf("%s command failed: %w", args[0], err)
 	}
-	err := ExecuteCmd(CmdConfig.Compiler.Command, args...)
+
+	data, err := yaml.Marshal(manifest)
 	if err != nil {
-		return fmt.Errorf("%s command failed: %w", CmdConfig.Compiler.Command, err)
+		return err
+	}
+	return os.WriteFile(ManifestPath(executable), data, 0o644)
+}
+
+// compileArgs returns the compile command of a source, including the include path of
+// the precompiled header when the source uses it.
+func compileArgs(name string, lang Language, source, executable string) []string {
+	args := lang.expand(lang.Compile, source, executable)
+	if dir := pchIncludeDir(name, source); dir != "" {
+		args = slices.Insert(args, 1, "-I", dir)
+	}
+	return args
+}
+
+// buildManifest records what an executable was built from, next to it.
+type buildManifest struct {
+	Source      string   `yaml:"source"`
+	Includes    []string `yaml:"includes,omitempty"`
+	Compiler    string   `yaml:"compiler"`
+	Fingerprint string   `yaml:"fingerprint"`
+}
+
+// ManifestPath returns the path of the build manifest of an executable.
+func ManifestPath(executable string) string {
+	return executable + ".build.yaml"
+}
+
+// newBuildManifest hashes the source, the local headers it includes and the compile
+// command with its flags, which together decide the resulting executable.
+func newBuildManifest(source string, command []string) (*buildManifest, error) {
+	includes, err := LocalIncludes(source)
+	if err != nil {
+		return nil, err
+	}
+	manifest := &buildManifest{
+		Source:   source,
+		Includes: includes,
+		Compiler: strings.Join(command, " "),
+	}
+
+	hash := sha256.New()
+	fmt.Fprintf(hash, "compiler %q\n", manifest.Compiler)
+	for _, file := range append([]string{source}, includes...) {
+		content, err := ReadFileToBytes(file)
+		if err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(hash, "file %q %d\n", file, len(content))
+		hash.Write(content)
+	}
+	manifest.Fingerprint = hex.EncodeToString(hash.Sum(nil))
+	return manifest, nil
+}
+
+// NeedsBuild reports whether the executable is missing, or was built from a different
+// source, headers, compiler or flags than the current ones according to its manifest.
+// Sources of interpreted languages never need a build.
+func NeedsBuild(source, executable string) (bool, error) {
+	name, lang, err := LanguageOf(source)
+	if err != nil {
+		return false, err
+	}
+	if lang.Compile == "" {
+		return false, nil
+	}
+	if !PathExists(lang.output(executable)) {
+		return true, nil
+	}
+
+	current, err := newBuildManifest(source, compileArgs(name, lang, source, executable))
+	if err != nil {
+		return false, err
+	}
+	data, err := os.ReadFile(ManifestPath(executable))
+	if os.IsNotExist(err) {
+		return true, nil
+	} else if err != nil {
+		return false, err
+	}
+	var recorded buildManifest
+	if err := yaml.Unmarshal(data, &recorded); err != nil {
+		return true, nil
+	}
+	return recorded.Fingerprint != current.Fingerprint, nil
+}
+
+// PrepareProgram returns a runnable path for a helper program such as a checker.
+// C++ sources are compiled next to themselves when they changed since the last build;
+// anything else is used as is.
+func PrepareProgram(path string, quiet bool) (string, error) {
+	switch filepath.Ext(path) {
+	case ".cpp", ".cc", ".cxx":
+		executable := strings.TrimSuffix(path, filepath.Ext(path))
+		outdated, err := NeedsBuild(path, executable)
+		if err != nil {
+			return "", err
+		}
+		if outdated {
+			if err := BuildSource(path, executable, quiet); err != nil {
+				return "", err
+			}
+		}
+		path = executable
+	}
+
+	// Run local executables by path rather than looking them up in $PATH
+	if !filepath.IsAbs(path) && !strings.ContainsRune(path, filepath.Separator) {
+		path = "." + string(filepath.Separator) + path
 	}
-	return nil
+	return path, nil
 }
@@ -2,8 +2,10 @@ package utils
 
 import (
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/ahmedYasserM/fo/internal/colors"
 	"gopkg.in/yaml.v3"
@@ -14,8 +16,18 @@ type Config struct {
 		Command string `yaml:"command"`
 		Flags   string `yaml:"flags"`
 	} `yaml:"compiler"`
-	SourceName     string `yaml:"source_name"`
-	ExecutableName string `yaml:"executable_name"`
+	SourceName        string              `yaml:"source_name"`
+	ExecutableName    string              `yaml:"executable_name"`
+	TimeLimit         time.Duration       `yaml:"time_limit"`
+	MemoryLimit       int                 `yaml:"memory_limit"`       // in megabytes
+	OutputLimit       int                 `yaml:"output_limit"`       // in megabytes
+	Checker           string              `yaml:"checker"`            // lines, tokens, nocase or float[:epsilon]
+	DiffMode          string              `yaml:"diff_mode"`          // unified or side
+	DisplayLimit      int                 `yaml:"display_limit"`      // lines shown of inputs and diffs, 0 for all
+	TestFormat        string              `yaml:"test_format"`        // txt, dir, polygon, yaml or json
+	PrecompiledHeader bool                `yaml:"precompiled_header"` // precompile bits/stdc++.h with g++
+	Profiles          map[string]Profile  `yaml:"profiles"`
+	Languages         map[string]Language `yaml:"languages"`
 }
 
 var (
@@ -31,8 +43,28 @@ var (
 			Command: "g++",
 			Flags:   "-Wall -Wextra -O2 -std=c++23",
 		},
-		SourceName:     "main.cpp",
-		ExecutableName: "main",
+		SourceName:        "main.cpp",
+		ExecutableName:    "main",
+		TimeLimit:         2 * time.Second,
+		MemoryLimit:       256,
+		OutputLimit:       64,
+		Checker:           "tokens",
+		DiffMode:          "unified",
+		DisplayLimit:      50,
+		TestFormat:        "txt",
+		PrecompiledHeader: true,
+		Profiles: map[string]Profile{
+			"release": {},
+			"debug": {
+				Flags:          "-Wall -Wextra -g -O0 -std=c++23 -D_GLIBCXX_DEBUG -D_GLIBCXX_DEBUG_PEDANTIC",
+				ExecutableName: "main-debug",
+			},
+			"asan": {
+				Flags:          "-Wall -Wextra -g -O1 -std=c++23 -fsanitize=address,undefined -fno-omit-frame-pointer",
+				ExecutableName: "main-asan",
+			},
+		},
+		Languages: defaultLanguages,
 	}
 )
 
@@ -80,7 +112,11 @@ func parseConfig(quiet bool) error {
 
 	if !PathExists(configPath) {
 		fmt.Fprintf(os.Stderr, "%s⚠️ Config file not found. Using defaults.%s\n", colors.YELLOW, colors.RESET)
-		CmdConfig = &defaultConfig
+		cfg := defaultConfig
+		cfg.Profiles = maps.Clone(defaultConfig.Profiles)
+		cfg.Languages = maps.Clone(defaultConfig.Languages)
+		CmdConfig = &cfg
+		detectSource()
 		return nil
 	}
 
@@ -89,9 +125,16 @@ func parseConfig(quiet bool) error {
 		return err
 	}
 
-	if err = yaml.Unmarshal(data, &CmdConfig); err != nil {
+	// Start from the defaults so that options missing from the file keep sane values;
+	// profiles and languages from the file are added to the default ones
+	cfg := defaultConfig
+	cfg.Profiles = maps.Clone(defaultConfig.Profiles)
+	cfg.Languages = maps.Clone(defaultConfig.Languages)
+	if err = yaml.Unmarshal(data, &cfg); err != nil {
 		return err
 	}
+	CmdConfig = &cfg
+	detectSource()
 
 	if !quiet {
 		fmt.Printf("%s✅ Config loaded successfully! %s\n", colors.GREEN, colors.RESET)
@@ -100,12 +143,17 @@ func parseConfig(quiet bool) error {
 	return nil
 }
 
+// parseTemplate loads the template of the language of the source file.
 func parseTemplate() error {
-	templatePath := filepath.Join(configDir, "template.cpp")
+	name, lang, err := LanguageOf(CmdConfig.SourceName)
+	if err != nil {
+		return err
+	}
+	templatePath := filepath.Join(configDir, lang.Template)
 
-	if !PathExists(templatePath) {
+	if lang.Template == "" || !PathExists(templatePath) {
 		fmt.Fprintf(os.Stderr, "%s⚠️ Template file not found. Using default template.%s\n", colors.YELLOW, colors.RESET)
-		CmdTemplate = defaultCppTemplate
+		CmdTemplate = defaultTemplates[name]
 		return nil
 	}
 
@@ -116,7 +164,7 @@ func parseTemplate() error {
 
 	CmdTemplate = content
 	templateLoaded = true
-	fmt.Printf("%s✅ C++ template loaded successfully! %s\n", colors.GREEN, colors.RESET)
+	fmt.Printf("%s✅ %s template loaded successfully! %s\n", colors.GREEN, name, colors.RESET)
 
 	return nil
 }
@@ -0,0 +1,39 @@
+package utils
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// localIncludeRegex matches #include "header" directives; <header> includes are system headers.
+var localIncludeRegex = regexp.MustCompile(`(?m)^\s*#\s*include\s*"([^"]+)"`)
+
+// LocalIncludes returns the headers included with #include "..." by source, directly or
+// through other local headers, in the order they are found. Headers are resolved relative
+// to the including file; those that do not exist there are skipped.
+func LocalIncludes(source string) ([]string, error) {
+	seen := map[string]bool{filepath.Clean(source): true}
+	var headers []string
+
+	queue := []string{source}
+	for len(queue) > 0 {
+		file := queue[0]
+		queue = queue[1:]
+
+		content, err := ReadFileToString(file)
+		if err != nil {
+			return nil, err
+		}
+		for _, match := range localIncludeRegex.FindAllStringSubmatch(content, -1) {
+			header := filepath.Clean(filepath.Join(filepath.Dir(file), strings.TrimSpace(match[1])))
+			if seen[header] || !PathExists(header) {
+				continue
+			}
+			seen[header] = true
+			headers = append(headers, header)
+			queue = append(queue, header)
+		}
+	}
+	return headers, nil
+}
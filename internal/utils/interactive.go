@@ -0,0 +1,174 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// InteractiveResult describes how a solution/interactor dialogue ended.
+type InteractiveResult struct {
+	Solution   *RunResult
+	Interactor *RunResult
+	Transcript string // empty unless requested
+}
+
+// transcript records the dialogue between the solution and the interactor line by line.
+type transcript struct {
+	mu      sync.Mutex
+	lines   []string
+	pending map[string]string // unfinished line per direction prefix
+}
+
+func (t *transcript) record(prefix string, p []byte) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	text := t.pending[prefix] + string(p)
+	for {
+		line, rest, found := strings.Cut(text, "\n")
+		if !found {
+			break
+		}
+		t.lines = append(t.lines, prefix+line)
+		text = rest
+	}
+	t.pending[prefix] = text
+}
+
+func (t *transcript) String() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	lines := t.lines
+	for _, prefix := range []string{"> ", "< "} {
+		if rest := t.pending[prefix]; rest != "" {
+			lines = append(lines, prefix+rest)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// teeWriter forwards a program's output to its peer while recording it.
+// Write errors are swallowed: the peer exiting early is judged by exit codes, not here.
+type teeWriter struct {
+	dst    io.Writer
+	log    *transcript
+	prefix string
+}
+
+func (w *teeWriter) Write(p []byte) (int, error) {
+	w.log.record(w.prefix, p)
+	w.dst.Write(p)
+	return len(p), nil
+}
+
+// RunInteractive runs a solution against an interactor, wiring the stdout of each
+// to the stdin of the other. The time limit applies to the whole dialogue and the
+// memory limit to the solution only. When withTranscript is set, the dialogue is
+// recorded with "> " marking solution output and "< " marking interactor output.
+func RunInteractive(ctx context.Context, opts RunOptions, solution []string, interactor []string, withTranscript bool) (*InteractiveResult, error) {
+	if opts.TimeLimit > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.TimeLimit)
+		defer cancel()
+	}
+
+	toInteractorR, toInteractorW, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+	toSolutionR, toSolutionW, err := os.Pipe()
+	if err != nil {
+		toInteractorR.Close()
+		toInteractorW.Close()
+		return nil, err
+	}
+
+	sol := limitedCommand(ctx, opts.MemoryLimit, solution[0], solution[1:]...)
+	addEnv(sol, opts.Env)
+	inter := limitedCommand(ctx, 0, interactor[0], interactor[1:]...)
+	var solErr, interErr bytes.Buffer
+	sol.Stdin, sol.Stderr = toSolutionR, &solErr
+	inter.Stdin, inter.Stderr = toInteractorR, &interErr
+
+	var log *transcript
+	if withTranscript {
+		log = &transcript{pending: make(map[string]string)}
+		sol.Stdout = &teeWriter{dst: toInteractorW, log: log, prefix: "> "}
+		inter.Stdout = &teeWriter{dst: toSolutionW, log: log, prefix: "< "}
+	} else {
+		sol.Stdout = toInteractorW
+		inter.Stdout = toSolutionW
+	}
+
+	start := time.Now()
+	startErr := inter.Start()
+	if startErr == nil {
+		if startErr = sol.Start(); startErr != nil {
+			killProcessGroup(inter)
+			inter.Wait()
+		}
+	}
+	// The children hold their own copies of the pipe ends now. The write ends stay
+	// open while a tee goroutine may still be forwarding to them.
+	toInteractorR.Close()
+	toSolutionR.Close()
+	if !withTranscript || startErr != nil {
+		toInteractorW.Close()
+		toSolutionW.Close()
+	}
+	if startErr != nil {
+		return nil, startErr
+	}
+
+	sampler := sampleMemory(sol.Process.Pid)
+	result := &InteractiveResult{
+		Solution:   &RunResult{},
+		Interactor: &RunResult{},
+	}
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		sol.Wait()
+		result.Solution.Wall = time.Since(start)
+		if withTranscript {
+			toInteractorW.Close()
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		inter.Wait()
+		result.Interactor.Wall = time.Since(start)
+		if withTranscript {
+			toSolutionW.Close()
+		}
+	}()
+	wg.Wait()
+
+	result.Solution.Stderr = solErr.String()
+	result.Interactor.Stderr = interErr.String()
+	usage := sampler.Stop()
+	usage.Virtual = max(usage.Virtual, imageSize(solution[0]))
+	fillUsage(result.Solution, sol, opts.MemoryLimit, usage)
+	fillUsage(result.Interactor, inter, 0, memoryUsage{})
+	if log != nil {
+		result.Transcript = log.String()
+	}
+
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		result.Solution.TimedOut = true
+	case ctx.Err() != nil:
+		return result, ctx.Err()
+	case opts.TimeLimit > 0 && result.Solution.CPU > opts.TimeLimit:
+		result.Solution.TimedOut = true
+	}
+	return result, nil
+}
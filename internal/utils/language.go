@@ -0,0 +1,245 @@
+package utils
+
+import (
+	"fmt"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Language describes how to build and run solutions written in a programming language.
+// Commands may use the placeholders {source}, {executable}, {compiler} and {flags},
+// the last two standing for compiler.command and compiler.flags.
+type Language struct {
+	Extensions []string `yaml:"extensions"`         // source extensions, the first one for new sources
+	Compile    string   `yaml:"compile,omitempty"`  // empty for interpreted languages
+	Output     string   `yaml:"output,omitempty"`   // what Compile produces, default {executable}
+	Run        string   `yaml:"run"`                // command running the solution
+	Template   string   `yaml:"template,omitempty"` // template file in the config directory
+	NoMemLimit bool     `yaml:"no_memory_limit"`    // the runtime reserves more address space than the memory limit
+}
+
+// defaultLanguages are the languages known without configuration.
+var defaultLanguages = map[string]Language{
+	"cpp": {
+		Extensions: []string{".cpp", ".cc", ".cxx"},
+		Compile:    "{compiler} {flags} {source} -o {executable}",
+		Run:        "{executable}",
+		Template:   "template.cpp",
+	},
+	"c": {
+		Extensions: []string{".c"},
+		Compile:    "gcc -Wall -O2 -std=c17 {source} -o {executable} -lm",
+		Run:        "{executable}",
+		Template:   "template.c",
+	},
+	"python": {
+		Extensions: []string{".py"},
+		Run:        "python3 {source}",
+		Template:   "template.py",
+	},
+	"java": {
+		Extensions: []string{".java"},
+		Compile:    "javac -d {executable}.classes {source}",
+		Output:     "{executable}.classes",
+		Run:        "java -Xss64m -cp {executable}.classes Main",
+		Template:   "template.java",
+		NoMemLimit: true,
+	},
+	"kotlin": {
+		Extensions: []string{".kt"},
+		Compile:    "kotlinc {source} -include-runtime -d {executable}.jar",
+		Output:     "{executable}.jar",
+		Run:        "java -Xss64m -jar {executable}.jar",
+		Template:   "template.kt",
+		NoMemLimit: true,
+	},
+	"rust": {
+		Extensions: []string{".rs"},
+		Compile:    "rustc -O --edition 2021 {source} -o {executable}",
+		Run:        "{executable}",
+		Template:   "template.rs",
+	},
+	"go": {
+		Extensions: []string{".go"},
+		Compile:    "go build -o {executable} {source}",
+		Run:        "{executable}",
+		Template:   "template.go",
+		NoMemLimit: true,
+	},
+}
+
+// LanguageOf returns the name and definition of the language of a source file.
+func LanguageOf(source string) (string, Language, error) {
+	ext := filepath.Ext(source)
+	for _, name := range slices.Sorted(maps.Keys(CmdConfig.Languages)) {
+		if lang := CmdConfig.Languages[name]; slices.Contains(lang.Extensions, ext) {
+			return name, lang, nil
+		}
+	}
+	return "", Language{}, fmt.Errorf("no language configured for %q files", ext)
+}
+
+// UseLanguage makes the source file of the named language the solution,
+// e.g. main.py instead of main.cpp for python.
+func UseLanguage(name string) error {
+	lang, ok := CmdConfig.Languages[name]
+	if !ok || len(lang.Extensions) == 0 {
+		names := slices.Sorted(maps.Keys(CmdConfig.Languages))
+		return fmt.Errorf("unknown language %q (available: %s)", name, strings.Join(names, ", "))
+	}
+	CmdConfig.SourceName = strings.TrimSuffix(CmdConfig.SourceName, filepath.Ext(CmdConfig.SourceName)) + lang.Extensions[0]
+	return nil
+}
+
+// detectSource switches to a solution in another language when the configured source
+// file does not exist, e.g. to main.py when there is no main.cpp.
+func detectSource() {
+	if PathExists(CmdConfig.SourceName) {
+		return
+	}
+	stem := strings.TrimSuffix(CmdConfig.SourceName, filepath.Ext(CmdConfig.SourceName))
+	for _, name := range slices.Sorted(maps.Keys(CmdConfig.Languages)) {
+		for _, ext := range CmdConfig.Languages[name].Extensions {
+			if PathExists(stem + ext) {
+				CmdConfig.SourceName = stem + ext
+				return
+			}
+		}
+	}
+}
+
+// expand fills in the placeholders of a command and splits it into arguments.
+func (l Language) expand(command, source, executable string) []string {
+	return strings.Fields(strings.NewReplacer(
+		"{source}", source,
+		"{executable}", localPath(executable),
+		"{compiler}", CmdConfig.Compiler.Command,
+		"{flags}", CmdConfig.Compiler.Flags,
+	).Replace(command))
+}
+
+// output returns the file or directory the compile command produces.
+func (l Language) output(executable string) string {
+	if l.Output == "" {
+		return executable
+	}
+	return strings.ReplaceAll(l.Output, "{executable}", executable)
+}
+
+// RunCommand returns the command running the solution from the current directory.
+func RunCommand() ([]string, error) {
+	return runCommand(CmdConfig.SourceName, CmdConfig.ExecutableName)
+}
+
+// AbsRunCommand is like RunCommand, but runs the solution from any working directory,
+// such as the one a problem with file-based I/O is run in.
+func AbsRunCommand() ([]string, error) {
+	source, err := filepath.Abs(CmdConfig.SourceName)
+	if err != nil {
+		return nil, err
+	}
+	executable, err := filepath.Abs(CmdConfig.ExecutableName)
+	if err != nil {
+		return nil, err
+	}
+	return runCommand(source, executable)
+}
+
+func runCommand(source, executable string) ([]string, error) {
+	_, lang, err := LanguageOf(source)
+	if err != nil {
+		return nil, err
+	}
+	return lang.expand(lang.Run, source, executable), nil
+}
+
+// IsCompiled reports whether the solution has to be compiled before running.
+func IsCompiled() bool {
+	_, lang, err := LanguageOf(CmdConfig.SourceName)
+	return err == nil && lang.Compile != ""
+}
+
+// MemoryLimitUnsupported explains why the address space limit used as memory limit
+// cannot be applied to the solution, or returns "" when it can.
+func MemoryLimitUnsupported() string {
+	if SanitizersEnabled() {
+		return "the sanitizer build"
+	}
+	if name, lang, err := LanguageOf(CmdConfig.SourceName); err == nil && lang.NoMemLimit {
+		return name
+	}
+	return ""
+}
+
+// localPath makes a relative path usable as a command, which would otherwise be looked up in $PATH.
+func localPath(path string) string {
+	if filepath.IsAbs(path) || strings.HasPrefix(path, "."+string(filepath.Separator)) || strings.HasPrefix(path, ".."+string(filepath.Separator)) {
+		return path
+	}
+	return "." + string(filepath.Separator) + path
+}
+
+// defaultTemplates are used for new sources when the config directory has no template.
+var defaultTemplates = map[string]string{
+	"cpp": defaultCppTemplate,
+	"c": `#include <stdio.h>
+
+int main(void) {
+
+  return 0;
+}
+`,
+	"python": `import sys
+
+input = sys.stdin.readline
+
+
+def main():
+    pass
+
+
+main()
+`,
+	"java": `import java.io.*;
+import java.util.*;
+
+class Main {
+  public static void main(String[] args) throws IOException {
+    BufferedReader in = new BufferedReader(new InputStreamReader(System.in));
+    PrintWriter out = new PrintWriter(new BufferedWriter(new OutputStreamWriter(System.out)));
+
+    out.flush();
+  }
+}
+`,
+	"kotlin": `fun main() {
+}
+`,
+	"rust": `use std::io::{self, Read, Write};
+
+fn main() {
+    let mut input = String::new();
+    io::stdin().read_to_string(&mut input).unwrap();
+    let mut out = io::BufWriter::new(io::stdout().lock());
+
+    out.flush().unwrap();
+}
+`,
+	"go": `package main
+
+import (
+	"bufio"
+	"os"
+)
+
+func main() {
+	in := bufio.NewReader(os.Stdin)
+	out := bufio.NewWriter(os.Stdout)
+	defer out.Flush()
+
+	_ = in
+}
+`,
+}
@@ -0,0 +1,103 @@
+//go:build linux
+
+package utils
+
+import (
+	"bytes"
+	"debug/elf"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"time"
+)
+
+// memorySampleInterval is how often the memory of a running program is sampled.
+const memorySampleInterval = 2 * time.Millisecond
+
+// memorySampler follows the VmHWM and VmPeak lines of /proc/<pid>/status while a program
+// runs. Unlike rusage they only count the program's own memory, but growth in its last
+// moments before exiting may be missed.
+type memorySampler struct {
+	done  chan struct{}
+	usage chan memoryUsage
+}
+
+// sampleMemory starts sampling the peak memory of a started process.
+func sampleMemory(pid int) *memorySampler {
+	// The file stays bound to the process, so it cannot be mistaken for another one
+	// reusing the pid once it is reaped
+	status, err := os.Open(fmt.Sprintf("/proc/%d/status", pid))
+	if err != nil {
+		return nil
+	}
+
+	s := &memorySampler{done: make(chan struct{}), usage: make(chan memoryUsage, 1)}
+	go func() {
+		defer status.Close()
+		ticker := time.NewTicker(memorySampleInterval)
+		defer ticker.Stop()
+
+		var usage memoryUsage
+		buf := make([]byte, 4096)
+		for {
+			if n, _ := status.ReadAt(buf, 0); n > 0 {
+				usage.Resident = max(usage.Resident, statusField(buf[:n], "VmHWM"))
+				usage.Virtual = max(usage.Virtual, statusField(buf[:n], "VmPeak"))
+			}
+			select {
+			case <-s.done:
+				s.usage <- usage
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return s
+}
+
+// Stop ends the sampling and returns the peaks seen, zero if none were.
+func (s *memorySampler) Stop() memoryUsage {
+	if s == nil {
+		return memoryUsage{}
+	}
+	close(s.done)
+	return <-s.usage
+}
+
+// statusField parses a line of a status file such as "VmHWM:   3116 kB" into bytes.
+func statusField(status []byte, name string) int64 {
+	_, line, found := bytes.Cut(status, []byte("\n"+name+":"))
+	if !found {
+		return 0
+	}
+	line, _, _ = bytes.Cut(line, []byte("\n"))
+	kb, err := strconv.ParseInt(string(bytes.TrimSuffix(bytes.TrimSpace(line), []byte(" kB"))), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return kb * 1024
+}
+
+// imageSize returns the memory the segments of an ELF executable take once loaded, such as
+// its global arrays, or 0 when name is not one. The kernel kills a program whose image does
+// not fit in the address space limit before it even starts.
+func imageSize(name string) int64 {
+	path, err := exec.LookPath(name)
+	if err != nil {
+		return 0
+	}
+	file, err := elf.Open(path)
+	if err != nil {
+		return 0
+	}
+	defer file.Close()
+
+	var size int64
+	for _, prog := range file.Progs {
+		if prog.Type == elf.PT_LOAD {
+			size += int64(prog.Memsz)
+		}
+	}
+	return size
+}
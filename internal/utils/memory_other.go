@@ -0,0 +1,21 @@
+//go:build !linux
+
+package utils
+
+// memorySampler is only implemented on Linux, elsewhere rusage is all there is.
+type memorySampler struct{}
+
+// sampleMemory does nothing on platforms without /proc.
+func sampleMemory(pid int) *memorySampler {
+	return nil
+}
+
+// Stop returns zero usage, as nothing was sampled.
+func (s *memorySampler) Stop() memoryUsage {
+	return memoryUsage{}
+}
+
+// imageSize is only known for the ELF executables of Linux.
+func imageSize(name string) int64 {
+	return 0
+}
@@ -0,0 +1,128 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/ahmedYasserM/fo/internal/colors"
+)
+
+// pchHeader is the header precompiled for C++ builds.
+const pchHeader = "bits/stdc++.h"
+
+var pchIncludeRegex = regexp.MustCompile(`(?m)^\s*#\s*include\s*<bits/stdc\+\+\.h>`)
+
+// PchCacheDir returns the directory holding the precompiled headers of all compiler settings.
+func PchCacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "fo", "pch"), nil
+}
+
+// PchDir returns the include directory of the precompiled header for the configured
+// compiler and flags. GCC only uses a precompiled header built with the same flags,
+// so each combination gets its own directory.
+func PchDir() (string, error) {
+	cacheDir, err := PchCacheDir()
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.New()
+	fmt.Fprintf(hash, "compiler %q\nflags %q\n", CmdConfig.Compiler.Command, CmdConfig.Compiler.Flags)
+	// An upgraded compiler rejects the headers precompiled by the previous version
+	if path, err := exec.LookPath(CmdConfig.Compiler.Command); err == nil {
+		if info, err := os.Stat(path); err == nil {
+			fmt.Fprintf(hash, "binary %q %d %d\n", path, info.Size(), info.ModTime().UnixNano())
+		}
+	}
+	return filepath.Join(cacheDir, hex.EncodeToString(hash.Sum(nil))[:16]), nil
+}
+
+// PchSupported reports whether the configured compiler picks up precompiled headers
+// from the include path, which is how GCC finds them.
+func PchSupported() bool {
+	return strings.Contains(filepath.Base(CmdConfig.Compiler.Command), "g++")
+}
+
+// BuildPch precompiles bits/stdc++.h with the configured compiler and flags into PchDir.
+func BuildPch(quiet bool) (string, error) {
+	if !PchSupported() {
+		return "", fmt.Errorf("precompiled headers need g++, the compiler is %s", CmdConfig.Compiler.Command)
+	}
+	dir, err := PchDir()
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Join(dir, filepath.Dir(pchHeader)), 0o755); err != nil {
+		return "", err
+	}
+
+	// The header is precompiled through a wrapper, as the real one lives in the compiler's directories.
+	// Every build writes its own wrapper and output, so concurrent builds cannot clobber each other
+	wrapper, err := os.CreateTemp(dir, "pch-*.h")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(wrapper.Name())
+	_, err = wrapper.WriteString("#include <" + pchHeader + ">\n")
+	if closeErr := wrapper.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", err
+	}
+
+	gch := filepath.Join(dir, pchHeader+".gch")
+	tmp, err := os.CreateTemp(filepath.Dir(gch), "*.gch.tmp")
+	if err != nil {
+		return "", err
+	}
+	tmp.Close()
+	defer os.Remove(tmp.Name())
+
+	if !quiet {
+		fmt.Printf("Precompiling %s%s%s, this takes a few seconds once...\n", colors.CYAN, pchHeader, colors.RESET)
+	}
+	args := strings.Fields(CmdConfig.Compiler.Flags)
+	args = append(args, "-x", "c++-header", wrapper.Name(), "-o", tmp.Name())
+	if err := ExecuteCmd(CmdConfig.Compiler.Command, args...); err != nil {
+		return "", fmt.Errorf("precompiling %s failed: %w", pchHeader, err)
+	}
+	// Renaming is atomic, so builds using the header never see a partial one
+	if err := os.Rename(tmp.Name(), gch); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+// PchBuilt reports whether the precompiled header for the configured compiler and flags exists.
+func PchBuilt() bool {
+	dir, err := PchDir()
+	return err == nil && PathExists(filepath.Join(dir, pchHeader+".gch"))
+}
+
+// pchIncludeDir returns the include directory to compile a source of the given language with
+// so that it uses the precompiled header, or "" when the source does not include bits/stdc++.h.
+func pchIncludeDir(language, source string) string {
+	if language != "cpp" || !CmdConfig.PrecompiledHeader || !PchSupported() {
+		return ""
+	}
+	content, err := ReadFileToString(source)
+	if err != nil || !pchIncludeRegex.MatchString(content) {
+		return ""
+	}
+	dir, err := PchDir()
+	if err != nil {
+		return ""
+	}
+	return dir
+}
@@ -0,0 +1,86 @@
+package utils
+
+import (
+	"cmp"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ProblemFile holds the metadata fetched for the problem in the current directory.
+const ProblemFile = "problem.yaml"
+
+// Problem describes a single problem as published by the judge, such as its limits,
+// along with per-problem settings such as the output checker.
+type Problem struct {
+	Title       string        `yaml:"title,omitempty"`
+	URL         string        `yaml:"url,omitempty"`
+	ContestID   string        `yaml:"contest_id,omitempty"`
+	ProblemID   string        `yaml:"problem_id,omitempty"` // index within the contest, e.g. A or C1
+	TimeLimit   time.Duration `yaml:"time_limit,omitempty"`
+	MemoryLimit int           `yaml:"memory_limit,omitempty"` // in megabytes
+	InputFile   string        `yaml:"input_file,omitempty"`   // read instead of stdin, e.g. input.txt
+	OutputFile  string        `yaml:"output_file,omitempty"`  // written instead of stdout
+	Checker     string        `yaml:"checker,omitempty"`
+	Interactor  string        `yaml:"interactor,omitempty"` // enables interactive tests
+}
+
+// CheckIOFile fails unless name is a plain file name such as input.txt, so that
+// file-based I/O cannot reach outside the directory the program runs in.
+func CheckIOFile(name string) error {
+	if name == "" || name == "." || name == ".." || filepath.Base(name) != name || strings.ContainsAny(name, `/\`) {
+		return fmt.Errorf("invalid I/O file name %q: expected a plain file name such as input.txt", name)
+	}
+	return nil
+}
+
+// LoadProblem reads problem.yaml from the current directory.
+// A missing file is not an error: an empty Problem is returned instead.
+func LoadProblem() (*Problem, error) {
+	return LoadProblemIn(".")
+}
+
+// LoadProblemIn reads problem.yaml from the problem directory dir.
+func LoadProblemIn(dir string) (*Problem, error) {
+	path := filepath.Join(dir, ProblemFile)
+	problem := &Problem{}
+	if !PathExists(path) {
+		return problem, nil
+	}
+
+	data, err := ReadFileToBytes(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := yaml.Unmarshal(data, problem); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", ProblemFile, err)
+	}
+	return problem, nil
+}
+
+// SaveProblemIn writes the problem metadata to problem.yaml in the problem directory dir.
+func SaveProblemIn(dir string, problem *Problem) error {
+	data, err := yaml.Marshal(problem)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, ProblemFile), data, 0o644)
+}
+
+// Merge takes over what the judge published about a problem from fetched, keeping
+// the per-problem settings and whatever fetched is missing.
+func (p *Problem) Merge(fetched *Problem) {
+	p.Title = cmp.Or(fetched.Title, p.Title)
+	p.URL = cmp.Or(fetched.URL, p.URL)
+	p.ContestID = cmp.Or(fetched.ContestID, p.ContestID)
+	p.ProblemID = cmp.Or(fetched.ProblemID, p.ProblemID)
+	p.TimeLimit = cmp.Or(fetched.TimeLimit, p.TimeLimit)
+	p.MemoryLimit = cmp.Or(fetched.MemoryLimit, p.MemoryLimit)
+	// Empty file names mean standard input and output
+	p.InputFile, p.OutputFile = fetched.InputFile, fetched.OutputFile
+}
@@ -0,0 +1,34 @@
+//go:build !unix
+
+package utils
+
+import (
+	"os"
+	"os/exec"
+)
+
+// setProcessGroup is a no-op on platforms without process groups.
+func setProcessGroup(cmd *exec.Cmd) {}
+
+// killProcessGroup kills the command itself on platforms without process groups.
+func killProcessGroup(cmd *exec.Cmd) error {
+	if cmd.Process == nil {
+		return nil
+	}
+	return cmd.Process.Kill()
+}
+
+// limitMemory leaves the command untouched on platforms without rlimits.
+func limitMemory(limit int64, name string, args []string) (string, []string) {
+	return name, args
+}
+
+// peakMemory is not available on platforms without rusage.
+func peakMemory(state *os.ProcessState, sampled int64) int64 {
+	return sampled
+}
+
+// exitSignal is not available on platforms without signals.
+func exitSignal(state *os.ProcessState) string {
+	return ""
+}
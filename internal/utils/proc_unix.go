@@ -0,0 +1,82 @@
+//go:build unix
+
+package utils
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"syscall"
+)
+
+// setProcessGroup makes the command the leader of a new process group.
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+// killProcessGroup kills the command together with every process it spawned.
+func killProcessGroup(cmd *exec.Cmd) error {
+	if cmd.Process == nil {
+		return nil
+	}
+	return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+}
+
+// limitMemory wraps the command in a shell that sets the address space rlimit
+// (in kilobytes) before exec'ing the program, so the limit is in place from the start.
+func limitMemory(limit int64, name string, args []string) (string, []string) {
+	script := fmt.Sprintf(`ulimit -v %d && exec "$0" "$@"`, limit/1024)
+	return "sh", append([]string{"-c", script, name}, args...)
+}
+
+// peakMemory returns the peak resident set size of a finished process in bytes, given
+// the peak sampled while it ran. A child shares our memory until it execs and the kernel
+// carries our own peak over into its rusage, so that figure only belongs to the program
+// when it is above ours; otherwise the sampled peak is used.
+func peakMemory(state *os.ProcessState, sampled int64) int64 {
+	rusage, ok := state.SysUsage().(*syscall.Rusage)
+	if !ok {
+		return sampled
+	}
+	peak := maxrssBytes(rusage.Maxrss)
+	var self syscall.Rusage
+	if sampled > 0 && syscall.Getrusage(syscall.RUSAGE_SELF, &self) == nil && peak <= maxrssBytes(self.Maxrss) {
+		return sampled
+	}
+	return max(peak, sampled)
+}
+
+// maxrssBytes converts a maxrss figure into bytes: Linux reports kilobytes, macOS bytes.
+func maxrssBytes(maxrss int64) int64 {
+	if runtime.GOOS == "darwin" {
+		return maxrss
+	}
+	return maxrss * 1024
+}
+
+// exitSignal returns the name of the signal that terminated the process, if any.
+func exitSignal(state *os.ProcessState) string {
+	status, ok := state.Sys().(syscall.WaitStatus)
+	if !ok || !status.Signaled() {
+		return ""
+	}
+	if name, ok := signalNames[status.Signal()]; ok {
+		return name
+	}
+	return fmt.Sprintf("signal %d", int(status.Signal()))
+}
+
+var signalNames = map[syscall.Signal]string{
+	syscall.SIGSEGV: "SIGSEGV",
+	syscall.SIGFPE:  "SIGFPE",
+	syscall.SIGABRT: "SIGABRT",
+	syscall.SIGBUS:  "SIGBUS",
+	syscall.SIGILL:  "SIGILL",
+	syscall.SIGKILL: "SIGKILL",
+	syscall.SIGTERM: "SIGTERM",
+	syscall.SIGPIPE: "SIGPIPE",
+	syscall.SIGXCPU: "SIGXCPU",
+	syscall.SIGXFSZ: "SIGXFSZ",
+	syscall.SIGTRAP: "SIGTRAP",
+}
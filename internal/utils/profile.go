@@ -0,0 +1,66 @@
+package utils
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+)
+
+// Profile is a named set of compiler flags with its own executable, e.g. a debug build.
+// Empty fields fall back to compiler.flags and executable_name.
+type Profile struct {
+	Flags          string `yaml:"flags"`
+	ExecutableName string `yaml:"executable_name"`
+}
+
+// ActiveProfile is the name of the profile selected by UseProfile, empty for none.
+var ActiveProfile string
+
+// UseProfile switches the compiler flags and executable name of the loaded config
+// to those of the named profile.
+func UseProfile(name string) error {
+	profile, ok := CmdConfig.Profiles[name]
+	if !ok {
+		names := slices.Sorted(maps.Keys(CmdConfig.Profiles))
+		return fmt.Errorf("unknown profile %q (available: %s)", name, strings.Join(names, ", "))
+	}
+
+	if profile.Flags != "" {
+		CmdConfig.Compiler.Flags = profile.Flags
+	}
+	if profile.ExecutableName != "" {
+		CmdConfig.ExecutableName = profile.ExecutableName
+	}
+	ActiveProfile = name
+	return nil
+}
+
+// SanitizersEnabled reports whether the configured flags build with sanitizers.
+func SanitizersEnabled() bool {
+	return strings.Contains(CmdConfig.Compiler.Flags, "-fsanitize")
+}
+
+// sanitizerOptions make sanitizers stop at the first error with a stack trace,
+// so that undefined behavior is reported as a runtime error of the test.
+// Leak detection is off: leaks do not matter in contest solutions.
+var sanitizerOptions = map[string]string{
+	"ASAN_OPTIONS":  "detect_leaks=0",
+	"UBSAN_OPTIONS": "halt_on_error=1:print_stacktrace=1",
+}
+
+// SanitizerEnv returns the sanitizer options to run a sanitized executable with,
+// leaving alone those set in the environment.
+func SanitizerEnv() []string {
+	if !SanitizersEnabled() {
+		return nil
+	}
+	var env []string
+	for _, name := range slices.Sorted(maps.Keys(sanitizerOptions)) {
+		if _, set := os.LookupEnv(name); !set {
+			env = append(env, name+"="+sanitizerOptions[name])
+		}
+	}
+	return env
+}
@@ -0,0 +1,227 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RunOptions controls a single limited execution of a program.
+type RunOptions struct {
+	Input       string
+	TimeLimit   time.Duration // 0 disables the limit
+	MemoryLimit int64         // in bytes, 0 disables the limit
+	OutputLimit int64         // in bytes, 0 disables the limit
+	Env         []string      // extra environment variables, e.g. sanitizer options
+	// InputFile and OutputFile replace stdin and stdout with files in a fresh working
+	// directory, for problems with file-based I/O. The command must not use relative paths.
+	InputFile  string
+	OutputFile string
+}
+
+// RunResult describes how a limited execution ended.
+type RunResult struct {
+	Stdout   string // possibly partial if the program crashed or was killed
+	Stderr   string
+	ExitCode int
+	Signal   string // name of the signal that killed the program, if any
+	Wall     time.Duration
+	CPU      time.Duration
+	Memory   int64 // peak resident set size in bytes, for display only
+	TimedOut bool
+	// MemoryExceeded is set when the program hit the memory limit, failing to allocate
+	// or dying from a signal with its address space at the limit.
+	MemoryExceeded bool
+	OutputExceeded bool
+}
+
+// Failed reports whether the program did not exit normally with code 0.
+func (r *RunResult) Failed() bool {
+	return r.ExitCode != 0 || r.Signal != ""
+}
+
+// limitedBuffer keeps at most limit bytes and calls onExceed once when more is written.
+type limitedBuffer struct {
+	mu       sync.Mutex
+	buf      bytes.Buffer
+	limit    int64
+	exceeded bool
+	onExceed func()
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.limit <= 0 {
+		return b.buf.Write(p)
+	}
+	if b.exceeded {
+		return len(p), nil
+	}
+	if room := b.limit - int64(b.buf.Len()); int64(len(p)) > room {
+		b.buf.Write(p[:room])
+		b.exceeded = true
+		b.onExceed()
+		return len(p), nil
+	}
+	return b.buf.Write(p)
+}
+
+// RunWithLimits runs a command in its own process group, feeding it the given input.
+// When the time limit expires the whole process group is killed and TimedOut is set.
+// The memory limit is enforced as an address space rlimit, and a program writing
+// more than the output limit is killed with OutputExceeded set.
+// A non-zero exit code or a fatal signal is reported through the result, not as an error.
+func RunWithLimits(ctx context.Context, opts RunOptions, name string, args ...string) (*RunResult, error) {
+	if opts.TimeLimit > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.TimeLimit)
+		defer cancel()
+	}
+	ctx, stop := context.WithCancelCause(ctx)
+	defer stop(nil)
+
+	cmd := limitedCommand(ctx, opts.MemoryLimit, name, args...)
+	addEnv(cmd, opts.Env)
+	cmd.Stdin = strings.NewReader(opts.Input)
+	errOutputLimit := errors.New("output limit exceeded")
+	stdout := &limitedBuffer{limit: opts.OutputLimit, onExceed: func() { stop(errOutputLimit) }}
+	var errb bytes.Buffer
+	cmd.Stdout = stdout
+	cmd.Stderr = &errb
+	if opts.InputFile != "" || opts.OutputFile != "" {
+		for _, name := range []string{opts.InputFile, opts.OutputFile} {
+			if name != "" {
+				if err := CheckIOFile(name); err != nil {
+					return nil, err
+				}
+			}
+		}
+		dir, err := os.MkdirTemp("", "fo-run-")
+		if err != nil {
+			return nil, err
+		}
+		defer os.RemoveAll(dir)
+		cmd.Dir = dir
+		if opts.InputFile != "" {
+			if err := os.WriteFile(filepath.Join(dir, opts.InputFile), []byte(opts.Input), 0o644); err != nil {
+				return nil, err
+			}
+			cmd.Stdin = nil
+		}
+	}
+
+	start := time.Now()
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	sampler := sampleMemory(cmd.Process.Pid)
+	err := cmd.Wait()
+	result := &RunResult{
+		Stdout: stdout.buf.String(),
+		Stderr: errb.String(),
+		Wall:   time.Since(start),
+	}
+	usage := sampler.Stop()
+	// A program whose image does not fit under the limit dies while being loaded
+	usage.Virtual = max(usage.Virtual, imageSize(name))
+	fillUsage(result, cmd, opts.MemoryLimit, usage)
+	if opts.OutputFile != "" {
+		// A missing output file reads as empty output, which the checker rejects
+		output, _ := os.ReadFile(filepath.Join(cmd.Dir, opts.OutputFile))
+		if opts.OutputLimit > 0 && int64(len(output)) > opts.OutputLimit {
+			result.Stdout = string(output[:opts.OutputLimit])
+			result.OutputExceeded = true
+			return result, nil
+		}
+		result.Stdout = string(output)
+	}
+
+	switch {
+	case errors.Is(context.Cause(ctx), errOutputLimit):
+		result.OutputExceeded = true
+		return result, nil
+	case errors.Is(ctx.Err(), context.DeadlineExceeded) || (opts.TimeLimit > 0 && result.CPU > opts.TimeLimit):
+		result.TimedOut = true
+		return result, nil
+	case ctx.Err() != nil:
+		return result, ctx.Err()
+	}
+
+	var exitErr *exec.ExitError
+	if err != nil && !errors.As(err, &exitErr) {
+		return result, err
+	}
+	return result, nil
+}
+
+// limitedCommand prepares a command running in its own process group under the
+// memory limit (in bytes, 0 for none), whose whole group is killed once ctx is done.
+func limitedCommand(ctx context.Context, memoryLimit int64, name string, args ...string) *exec.Cmd {
+	if memoryLimit > 0 {
+		name, args = limitMemory(memoryLimit, name, args)
+	}
+
+	cmd := exec.CommandContext(ctx, name, args...)
+	setProcessGroup(cmd)
+	cmd.Cancel = func() error { return killProcessGroup(cmd) }
+	// Grandchildren holding the output pipes must not keep us waiting forever
+	cmd.WaitDelay = time.Second
+	return cmd
+}
+
+// addEnv runs the command with extra environment variables on top of ours.
+func addEnv(cmd *exec.Cmd, env []string) {
+	if len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
+}
+
+// memoryUsage is what was seen of the memory of a program while it ran.
+type memoryUsage struct {
+	Resident int64 // peak resident set size in bytes, 0 if unknown
+	Virtual  int64 // peak address space size in bytes, 0 if unknown
+}
+
+// fillUsage records the exit status and resource usage of a finished command, given
+// the memory usage sampled while it ran.
+//
+// The address space rlimit does not kill a program exceeding it: its allocations fail
+// instead, throwing bad_alloc or returning NULL, and its stack cannot grow any more.
+// So the limit counts as exceeded when the program failed on an allocation, or died
+// from a signal with its address space at the limit.
+func fillUsage(result *RunResult, cmd *exec.Cmd, memoryLimit int64, usage memoryUsage) {
+	if cmd.ProcessState != nil {
+		result.ExitCode = cmd.ProcessState.ExitCode()
+		result.Signal = exitSignal(cmd.ProcessState)
+		result.CPU = cmd.ProcessState.UserTime() + cmd.ProcessState.SystemTime()
+		result.Memory = peakMemory(cmd.ProcessState, usage.Resident)
+	}
+	if memoryLimit > 0 && result.Failed() {
+		result.MemoryExceeded = isAllocationFailure(result.Stderr) ||
+			(result.Signal != "" && usage.Virtual >= memoryLimit-memoryLimitSlack(memoryLimit))
+	}
+}
+
+// memoryLimitSlack is how far below the memory limit the sampled address space may
+// stay and still count as having reached it, as it is only sampled now and then.
+func memoryLimitSlack(memoryLimit int64) int64 {
+	return memoryLimit / 10
+}
+
+// isAllocationFailure reports whether stderr looks like the program ran out of memory.
+func isAllocationFailure(stderr string) bool {
+	for _, message := range []string{"bad_alloc", "Cannot allocate memory", "MemoryError", "memory allocation of"} {
+		if strings.Contains(stderr, message) {
+			return true
+		}
+	}
+	return false
+}
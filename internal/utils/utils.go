@@ -2,6 +2,7 @@ package utils
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
@@ -21,6 +22,17 @@ func ExecuteCmd(name string, args ...string) error {
 	return cmd.Run()
 }
 
+// ExecuteCmdContext is like ExecuteCmd, but kills the command when ctx is done
+// and adds env to the environment of the command.
+func ExecuteCmdContext(ctx context.Context, env []string, name string, args ...string) error {
+	cmd := exec.CommandContext(ctx, name, args...)
+	addEnv(cmd, env)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
 // ExecuteCmdWithInput runs a shell command with provided input and returns output.
 func ExecuteCmdWithInput(input string, name string, args ...string) (string, error) {
 	cmd := exec.Command(name, args...)
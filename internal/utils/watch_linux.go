@@ -0,0 +1,167 @@
+//go:build linux
+
+package utils
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// watchEvents are the inotify events that mean a file was written, replaced or removed.
+// Editors that save by renaming a temporary file produce IN_MOVED_TO instead of IN_CLOSE_WRITE.
+const watchEvents = unix.IN_CLOSE_WRITE | unix.IN_MOVED_TO | unix.IN_MOVED_FROM | unix.IN_CREATE | unix.IN_DELETE
+
+// pollInterval is how often the watcher checks whether it was stopped.
+const pollInterval = 200 * time.Millisecond
+
+// Watcher reports changes of a set of files and directories using inotify.
+// The directories holding the files are watched, so files that are replaced or
+// do not exist yet are noticed too.
+type Watcher struct {
+	fd int
+
+	mu      sync.Mutex
+	dirs    map[int]string  // watch descriptor to watched directory
+	targets map[string]bool // absolute paths of the watched files and directories
+}
+
+// NewWatcher creates a watcher without any watched paths.
+func NewWatcher() (*Watcher, error) {
+	fd, err := unix.InotifyInit1(unix.IN_CLOEXEC | unix.IN_NONBLOCK)
+	if err != nil {
+		return nil, os.NewSyscallError("inotify_init1", err)
+	}
+	return &Watcher{fd: fd, dirs: map[int]string{}, targets: map[string]bool{}}, nil
+}
+
+// Watch replaces the watched paths. Paths may be files or directories, and need not exist
+// as long as their parent directory does; changes anywhere inside a directory count.
+func (w *Watcher) Watch(paths []string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.targets = map[string]bool{}
+	for _, path := range paths {
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			return err
+		}
+		w.targets[abs] = true
+
+		dirs := []string{filepath.Dir(abs)}
+		if info, err := os.Stat(abs); err == nil && info.IsDir() {
+			dirs = append(dirs, abs)
+		}
+		for _, dir := range dirs {
+			// Watching a directory twice returns the same descriptor
+			wd, err := unix.InotifyAddWatch(w.fd, dir, watchEvents)
+			if err != nil {
+				return &os.PathError{Op: "inotify_add_watch", Path: dir, Err: err}
+			}
+			w.dirs[wd] = dir
+		}
+	}
+	return nil
+}
+
+// Changes delivers a notification after each burst of changes of the watched paths,
+// once no further change arrived for debounce. The channel is closed when ctx is done.
+func (w *Watcher) Changes(ctx context.Context, debounce time.Duration) <-chan struct{} {
+	events := make(chan struct{})
+	go w.readEvents(ctx, events)
+
+	changes := make(chan struct{})
+	go func() {
+		defer close(changes)
+		var timer <-chan time.Time
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case _, ok := <-events:
+				if !ok {
+					return
+				}
+				timer = time.After(debounce)
+			case <-timer:
+				timer = nil
+				select {
+				case changes <- struct{}{}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return changes
+}
+
+// readEvents sends an event for every inotify event about a watched path until ctx is done.
+func (w *Watcher) readEvents(ctx context.Context, events chan<- struct{}) {
+	defer close(events)
+	buf := make([]byte, 64*1024)
+	for ctx.Err() == nil {
+		fds := []unix.PollFd{{Fd: int32(w.fd), Events: unix.POLLIN}}
+		n, err := unix.Poll(fds, int(pollInterval.Milliseconds()))
+		if err != nil && !errors.Is(err, unix.EINTR) {
+			return
+		}
+		if n <= 0 {
+			continue
+		}
+
+		n, err = unix.Read(w.fd, buf)
+		if err != nil {
+			if errors.Is(err, unix.EAGAIN) || errors.Is(err, unix.EINTR) {
+				continue
+			}
+			return
+		}
+		for offset := 0; offset+unix.SizeofInotifyEvent <= n; {
+			event := (*unix.InotifyEvent)(unsafe.Pointer(&buf[offset]))
+			nameBytes := buf[offset+unix.SizeofInotifyEvent : offset+unix.SizeofInotifyEvent+int(event.Len)]
+			offset += unix.SizeofInotifyEvent + int(event.Len)
+
+			if w.matches(int(event.Wd), string(bytesBeforeNul(nameBytes))) {
+				select {
+				case events <- struct{}{}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}
+}
+
+// matches reports whether an event about name in the directory watched by wd concerns a target.
+func (w *Watcher) matches(wd int, name string) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	dir, ok := w.dirs[wd]
+	if !ok || name == "" {
+		return false
+	}
+	return w.targets[dir] || w.targets[filepath.Join(dir, name)]
+}
+
+// Close releases the inotify instance.
+func (w *Watcher) Close() error {
+	return unix.Close(w.fd)
+}
+
+func bytesBeforeNul(b []byte) []byte {
+	for i, c := range b {
+		if c == 0 {
+			return b[:i]
+		}
+	}
+	return b
+}
@@ -0,0 +1,27 @@
+//go:build !linux
+
+package utils
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Watcher reports changes of a set of files; it is only implemented on Linux.
+type Watcher struct{}
+
+// NewWatcher fails on platforms without inotify.
+func NewWatcher() (*Watcher, error) {
+	return nil, errors.New("watch mode is only supported on Linux")
+}
+
+func (w *Watcher) Watch(paths []string) error { return nil }
+
+func (w *Watcher) Changes(ctx context.Context, debounce time.Duration) <-chan struct{} {
+	changes := make(chan struct{})
+	close(changes)
+	return changes
+}
+
+func (w *Watcher) Close() error { return nil }